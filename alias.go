@@ -0,0 +1,79 @@
+package blend
+
+import (
+	"fmt"
+	"sync"
+)
+
+var (
+	aliasesMu sync.RWMutex
+	// names of each field with aliases by struct and field name, the canonical name first
+	aliases = map[string]map[string][]string{}
+)
+
+func init() {
+	RegisterFieldAlias("Object", "object_to_world", "obmat")
+	RegisterFieldAlias("Object", "world_to_object", "imat")
+	RegisterFieldAlias("Object", "instance_collection", "dup_group")
+	RegisterFieldAlias("Object", "empty_display_type", "empty_drawtype")
+	RegisterFieldAlias("Object", "empty_display_size", "empty_drawsize")
+	RegisterFieldAlias("Object", "visibility_flag", "restrictflag")
+	RegisterFieldAlias("Collection", "instance_offset", "dupli_ofs")
+	RegisterFieldAlias("Camera", "display_size", "drawsize")
+}
+
+// RegisterFieldAlias registers former names of the field canonicalName of the SDNA struct structName,
+// e.g. "obmat" for the "object_to_world" field of an Object. Fields are then found by any of these names,
+// whichever of them a file uses, with the name asked for being tried first.
+// Registering a field again replaces its previous aliases, and so does registering one of them as a field.
+func RegisterFieldAlias(structName, canonicalName string, aliasNames ...string) {
+	names := append([]string{canonicalName}, aliasNames...)
+
+	aliasesMu.Lock()
+	defer aliasesMu.Unlock()
+	if aliases[structName] == nil {
+		aliases[structName] = make(map[string][]string)
+	}
+	for _, name := range names {
+		// drop the group the name belonged to so that none of its names keeps a stale alias
+		for _, stale := range aliases[structName][name] {
+			delete(aliases[structName], stale)
+		}
+	}
+	for _, name := range names {
+		aliases[structName][name] = names
+	}
+}
+
+// lookupAliases returns the names of a field including the field name itself, or nil if it has no aliases.
+func lookupAliases(structName, fieldName string) []string {
+	aliasesMu.RLock()
+	defer aliasesMu.RUnlock()
+	return aliases[structName][fieldName]
+}
+
+// FieldOffsetAny returns the offset of the field canonicalName, or of the first of its aliases registered by
+// RegisterFieldAlias the structure at index structIdx of Structs has, from the start of the structure in bytes.
+func (s *StructureDNA) FieldOffsetAny(structIdx int, canonicalName string) (int, error) {
+	if structIdx < 0 || structIdx >= len(s.layouts) {
+		return 0, fmt.Errorf("blend: struct index %d out of range", structIdx)
+	}
+	l, ok := s.fieldAny(structIdx, canonicalName)
+	if !ok {
+		return 0, fmt.Errorf("blend: %s has no field '%s'", s.typeName(structIdx), canonicalName)
+	}
+	return l.offset, nil
+}
+
+// fieldAny looks up a field by name within the structure at index idx, falling back to its aliases.
+func (s *StructureDNA) fieldAny(idx int, name string) (fieldLayout, bool) {
+	if l, ok := s.field(idx, name); ok {
+		return l, true
+	}
+	for _, alias := range lookupAliases(s.typeName(idx), name) {
+		if l, ok := s.field(idx, alias); ok {
+			return l, true
+		}
+	}
+	return fieldLayout{}, false
+}
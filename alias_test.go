@@ -0,0 +1,79 @@
+package blend
+
+import "testing"
+
+func TestStructureDNA_FieldOffsetAny(t *testing.T) {
+	f := readExampleFile(t, "cubus-animated.blend")
+	sdna, err := f.SDNA()
+	if err != nil {
+		t.Fatalf("Expected nil error, got: %v", err)
+	}
+	idx, _ := sdna.StructIndexByName("Object")
+	fields, err := sdna.Fields(idx)
+	if err != nil {
+		t.Fatalf("Expected nil error, got: %v", err)
+	}
+	offsets := map[string]int{}
+	for _, field := range fields {
+		offsets[field.Name] = field.Offset
+	}
+
+	for name, expected := range map[string]string{
+		"instance_collection": "dup_group",
+		"dup_group":           "dup_group",
+		"object_to_world":     "obmat",
+		"parent":              "parent",
+	} {
+		offset, err := sdna.FieldOffsetAny(idx, name)
+		if err != nil {
+			t.Fatalf("Expected nil error, got: %v", err)
+		}
+		if offset != offsets[expected] {
+			t.Errorf("expected '%s' at the offset %d of '%s', got %d", name, offsets[expected], expected, offset)
+		}
+	}
+
+	if _, err := sdna.FieldOffsetAny(idx, "nosuchfield"); err == nil {
+		t.Error("expected error for unknown field")
+	}
+	if _, err := sdna.FieldOffsetAny(-1, "parent"); err == nil {
+		t.Error("expected error for struct index out of range")
+	}
+}
+
+func TestRegisterFieldAlias(t *testing.T) {
+	fx := newFixture(t)
+	// a file using the current name of the field the decoder reads by its former name
+	sdna := *fx.f.sdna
+	sdna.Names = append([]string{}, sdna.Names...)
+	for i, name := range sdna.Names {
+		if name == "obmat[4][4]" {
+			sdna.Names[i] = "object_to_world[4][4]"
+		}
+	}
+	dna := fx.block(CodeDNA1, 0)
+	dna.Data = encodeSDNA(fx.f.order, &sdna)
+	dna.Header.Size = uint32(len(dna.Data))
+	f := fx.file()
+
+	objects, err := f.SceneObjects(f.fileBlocks[CodeScene][0].Header.OldMemoryAddress)
+	if err != nil {
+		t.Fatalf("Expected nil error, got: %v", err)
+	}
+	if len(objects) == 0 || objects[0].Matrix[3] != [4]float32{0, 0, 0, 1} {
+		t.Errorf("expected matrix of the cube read from 'object_to_world', got: %+v", objects)
+	}
+
+	RegisterFieldAlias("Object", "custom_alias_test", "parent")
+	idx, _ := f.sdna.StructIndexByName("Object")
+	if ok, _ := f.sdna.HasField("Object", "custom_alias_test"); ok {
+		t.Error("expected HasField to match exact names only")
+	}
+	if _, err := f.sdna.FieldOffsetAny(idx, "custom_alias_test"); err != nil {
+		t.Errorf("expected field to be found by its alias, got: %v", err)
+	}
+	RegisterFieldAlias("Object", "custom_alias_test")
+	if names := lookupAliases("Object", "parent"); names != nil {
+		t.Errorf("expected aliases to be replaced, got: %v", names)
+	}
+}
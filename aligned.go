@@ -0,0 +1,44 @@
+package blend
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+)
+
+// alignedReader reads sections of block data which start at aligned positions, like the sections of the SDNA.
+type alignedReader struct {
+	*bytes.Reader
+}
+
+// Pos returns the position of the next byte to read relative to the start of the data.
+func (r *alignedReader) Pos() int64 {
+	return r.Size() - int64(r.Len())
+}
+
+// Align skips the padding up to the next position which is a multiple of n, unless the current one already is.
+func (r *alignedReader) Align(n int) error {
+	if n <= 0 {
+		return fmt.Errorf("blend: invalid alignment %d", n)
+	}
+	pos := r.Pos()
+	if pad := (int64(n) - pos%int64(n)) % int64(n); pad > 0 {
+		if pad > int64(r.Len()) {
+			return fmt.Errorf("%w: %d bytes of padding exceed the remaining %d bytes", ErrShortBlockData, pad, r.Len())
+		}
+		if _, err := r.Seek(pad, io.SeekCurrent); err != nil {
+			return fmt.Errorf("blend: unable to skip padding: %w", err)
+		}
+	}
+	return nil
+}
+
+// ReadMagic reads the four character identifier starting a section.
+func (r *alignedReader) ReadMagic() ([4]byte, error) {
+	var magic [4]byte
+	if r.Len() < len(magic) {
+		return magic, fmt.Errorf("%w: identifier exceeds the remaining %d bytes", ErrShortBlockData, r.Len())
+	}
+	_, err := io.ReadFull(r, magic[:])
+	return magic, err
+}
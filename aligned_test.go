@@ -0,0 +1,56 @@
+package blend
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"testing"
+)
+
+func TestAlignedReader_Align(t *testing.T) {
+	testTable := []struct {
+		pos, n, expected int64
+	}{
+		{0, 4, 0},
+		{1, 4, 4},
+		{3, 4, 4},
+		{4, 4, 4},
+		{13, 4, 16},
+		{13, 8, 16},
+		{16, 8, 16},
+		{13, 1, 13},
+	}
+	for _, tt := range testTable {
+		r := &alignedReader{Reader: bytes.NewReader(make([]byte, 20))}
+		r.Seek(tt.pos, io.SeekStart)
+		if err := r.Align(int(tt.n)); err != nil {
+			t.Fatalf("Expected nil error, got: %v", err)
+		}
+		if pos := r.Pos(); pos != tt.expected {
+			t.Errorf("expected position %d to align to %d for alignment %d, got %d", tt.pos, tt.expected, tt.n, pos)
+		}
+	}
+
+	r := &alignedReader{Reader: bytes.NewReader(make([]byte, 14))}
+	r.Seek(13, io.SeekStart)
+	if err := r.Align(4); !errors.Is(err, ErrShortBlockData) {
+		t.Errorf("expected ErrShortBlockData aligning past the end, got: %v", err)
+	}
+	if err := r.Align(0); err == nil {
+		t.Error("expected error for alignment 0")
+	}
+}
+
+func TestAlignedReader_ReadMagic(t *testing.T) {
+	r := &alignedReader{Reader: bytes.NewReader([]byte("SDNANAM"))}
+	magic, err := r.ReadMagic()
+	if err != nil {
+		t.Fatalf("Expected nil error, got: %v", err)
+	}
+	if string(magic[:]) != "SDNA" || r.Pos() != 4 {
+		t.Errorf("expected 'SDNA' followed by position 4, got %q at %d", magic, r.Pos())
+	}
+	if _, err := r.ReadMagic(); !errors.Is(err, ErrShortBlockData) {
+		t.Errorf("expected ErrShortBlockData, got: %v", err)
+	}
+}
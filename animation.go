@@ -0,0 +1,313 @@
+package blend
+
+import "fmt"
+
+// Keyframe is a single key of an F-Curve.
+type Keyframe struct {
+	// Frame the key is placed at
+	Frame float32
+	// Value of the animated property at Frame
+	Value float32
+	// Frame and value of the handle before the key
+	HandleLeft [2]float32
+	// Frame and value of the handle after the key
+	HandleRight [2]float32
+	// Interpolation towards the next key, e.g. BEZIER
+	Interpolation string
+}
+
+// interpolationNames maps the values of a BezTriple's `ipo` field to the names used by the Python API.
+var interpolationNames = map[int]string{
+	0:  "CONSTANT",
+	1:  "LINEAR",
+	2:  "BEZIER",
+	3:  "BACK",
+	4:  "BOUNCE",
+	5:  "CIRC",
+	6:  "CUBIC",
+	7:  "ELASTIC",
+	8:  "EXPO",
+	9:  "QUAD",
+	10: "QUART",
+	11: "QUINT",
+	12: "SINE",
+}
+
+// FCurveKeyframes decodes the keys of the F-Curve located at fcurveAddr.
+// Baked F-Curves store sampled points instead of keys, which are returned as keys interpolated linearly
+// with both handles placed on the point itself.
+func (f *File) FCurveKeyframes(fcurveAddr uint64) ([]Keyframe, error) {
+	fcu, err := f.structAt(fcurveAddr, "FCurve")
+	if err != nil {
+		return nil, err
+	}
+	total, err := fcu.int("totvert")
+	if err != nil {
+		return nil, err
+	}
+	bezt, err := fcu.pointer("bezt")
+	if err != nil {
+		return nil, err
+	}
+	fpt, err := fcu.pointer("fpt")
+	if err != nil {
+		return nil, err
+	}
+	switch {
+	case total <= 0:
+		return []Keyframe{}, nil
+	case bezt != 0:
+		return f.bezTripleKeyframes(bezt, int(total))
+	case fpt != 0:
+		return f.sampledKeyframes(fpt, int(total))
+	}
+	return nil, fmt.Errorf("blend: F-Curve at %#x has %d keys but no data", fcurveAddr, total)
+}
+
+// bezTripleKeyframes decodes n keys from the BezTriple array located at addr.
+// Each BezTriple holds the left handle, the key and the right handle as its three vectors.
+func (f *File) bezTripleKeyframes(addr uint64, n int) ([]Keyframe, error) {
+	b, err := f.blockByAddress(addr)
+	if err != nil {
+		return nil, err
+	}
+	if err := f.checkInstances(b, n); err != nil {
+		return nil, err
+	}
+	keys := make([]Keyframe, n)
+	for i := range keys {
+		bt, err := f.blockInstance(b, i)
+		if err != nil {
+			return nil, err
+		}
+		_, raw, err := bt.field("vec")
+		if err != nil {
+			return nil, err
+		}
+		vec := f.decodeVectors(raw)
+		if len(vec) != 3 {
+			return nil, fmt.Errorf("blend: expected 3 vectors in BezTriple %d at %#x, got %d", i, addr, len(vec))
+		}
+		ipo, err := bt.int("ipo")
+		if err != nil {
+			return nil, err
+		}
+		keys[i] = Keyframe{
+			Frame:         vec[1][0],
+			Value:         vec[1][1],
+			HandleLeft:    [2]float32{vec[0][0], vec[0][1]},
+			HandleRight:   [2]float32{vec[2][0], vec[2][1]},
+			Interpolation: interpolationNames[int(ipo)],
+		}
+		if keys[i].Interpolation == "" {
+			keys[i].Interpolation = "UNKNOWN"
+		}
+	}
+	return keys, nil
+}
+
+// sampledKeyframes decodes n sampled points from the FPoint array located at addr.
+func (f *File) sampledKeyframes(addr uint64, n int) ([]Keyframe, error) {
+	b, err := f.blockByAddress(addr)
+	if err != nil {
+		return nil, err
+	}
+	if err := f.checkInstances(b, n); err != nil {
+		return nil, err
+	}
+	keys := make([]Keyframe, n)
+	for i := range keys {
+		pt, err := f.blockInstance(b, i)
+		if err != nil {
+			return nil, err
+		}
+		_, raw, err := pt.field("vec")
+		if err != nil {
+			return nil, err
+		}
+		var point [2]float32
+		for j := range point {
+			v, err := f.decodeFloat("float", raw[4*j:])
+			if err != nil {
+				return nil, err
+			}
+			point[j] = float32(v)
+		}
+		keys[i] = Keyframe{
+			Frame:         point[0],
+			Value:         point[1],
+			HandleLeft:    point,
+			HandleRight:   point,
+			Interpolation: "LINEAR",
+		}
+	}
+	return keys, nil
+}
+
+// AnimData summarizes what animates a datablock.
+type AnimData struct {
+	// Name of the active action without its ID code, empty if there is none
+	Action string
+	// Names of the NLA tracks from bottom to top
+	NLATracks []string
+	// Drivers of the properties of the datablock
+	Drivers []Driver
+}
+
+// Driver is a driver F-Curve computing the value of a property from the values of others.
+type Driver struct {
+	// RNA path of the driven property, e.g. "location"
+	Path string
+	// Index of the driven element of an array property, e.g. 2 for the z location
+	Index int
+	// Properties read by the variables of the driver
+	Targets []DriverTarget
+}
+
+// DriverTarget is a property read by a driver variable.
+type DriverTarget struct {
+	// Name of the datablock the property belongs to without its ID code, empty if it is not set
+	ID string
+	// RNA path of the property relative to the datablock, e.g. "location.x"
+	Path string
+}
+
+// AnimData returns the action, NLA tracks and drivers animating the object located at objectAddr,
+// or nil if the object is not animated.
+func (f *File) AnimData(objectAddr uint64) (*AnimData, error) {
+	ob, err := f.structAt(objectAddr, "Object")
+	if err != nil {
+		return nil, err
+	}
+	addr, err := ob.pointer("adt")
+	if err != nil || addr == 0 {
+		return nil, err
+	}
+	adt, err := f.structAt(addr, "AnimData")
+	if err != nil {
+		return nil, err
+	}
+	data := &AnimData{
+		NLATracks: []string{},
+		Drivers:   []Driver{},
+	}
+
+	action, err := adt.pointer("action")
+	if err != nil {
+		return nil, err
+	}
+	if action != 0 {
+		ac, err := f.structAt(action, "bAction")
+		if err != nil {
+			return nil, err
+		}
+		if data.Action, err = ac.idName(); err != nil {
+			return nil, err
+		}
+	}
+
+	first, err := adt.pointer("nla_tracks.first")
+	if err != nil {
+		return nil, err
+	}
+	err = f.walkList(first, func(track *instance) error {
+		name, err := track.string("name")
+		if err != nil {
+			return err
+		}
+		data.NLATracks = append(data.NLATracks, name)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	first, err = adt.pointer("drivers.first")
+	if err != nil {
+		return nil, err
+	}
+	err = f.walkList(first, func(fcu *instance) error {
+		d, err := f.driver(fcu)
+		if err != nil {
+			return err
+		}
+		data.Drivers = append(data.Drivers, *d)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return data, nil
+}
+
+// driver decodes the driven property and the targets of the driver F-Curve fcu.
+func (f *File) driver(fcu *instance) (*Driver, error) {
+	path, err := fcu.stringPointer("rna_path")
+	if err != nil {
+		return nil, err
+	}
+	index, err := fcu.int("array_index")
+	if err != nil {
+		return nil, err
+	}
+	d := &Driver{
+		Path:    path,
+		Index:   int(index),
+		Targets: []DriverTarget{},
+	}
+	addr, err := fcu.pointer("driver")
+	if err != nil || addr == 0 {
+		return d, err
+	}
+	driver, err := f.structAt(addr, "ChannelDriver")
+	if err != nil {
+		return nil, err
+	}
+	first, err := driver.pointer("variables.first")
+	if err != nil {
+		return nil, err
+	}
+	err = f.walkList(first, func(dvar *instance) error {
+		n, err := dvar.int("num_targets")
+		if err != nil {
+			return err
+		}
+		for i := 0; i < int(n); i++ {
+			target, err := dvar.element("targets", i)
+			if err != nil {
+				return err
+			}
+			t, err := f.driverTarget(target)
+			if err != nil {
+				return err
+			}
+			d.Targets = append(d.Targets, t)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return d, nil
+}
+
+// driverTarget decodes the datablock and property a DriverTarget reads.
+func (f *File) driverTarget(target *instance) (DriverTarget, error) {
+	path, err := target.stringPointer("rna_path")
+	if err != nil {
+		return DriverTarget{}, err
+	}
+	t := DriverTarget{Path: path}
+	id, err := target.pointer("id")
+	if err != nil || id == 0 {
+		return t, err
+	}
+	in, err := f.instanceAt(id)
+	if err != nil {
+		return DriverTarget{}, err
+	}
+	if t.ID, err = in.idName(); err != nil {
+		return DriverTarget{}, err
+	}
+	return t, nil
+}
@@ -0,0 +1,167 @@
+package blend
+
+import (
+	"math"
+	"reflect"
+	"testing"
+)
+
+func TestFile_FCurveKeyframes(t *testing.T) {
+	fx := newFixture(t)
+	fcu := rotationFCurve(t, fx.f, 2)
+
+	keys, err := fx.f.FCurveKeyframes(fcu.Header.OldMemoryAddress)
+	if err != nil {
+		t.Fatalf("Expected nil error, got: %v", err)
+	}
+	if len(keys) != 2 {
+		t.Fatalf("expected 2 keys, got: %v", keys)
+	}
+	// the cube turns from 390 to 30 degrees around z between frame 1 and 100
+	expected := []Keyframe{
+		{Frame: 1, Value: 390, HandleLeft: [2]float32{-32, 390}, HandleRight: [2]float32{34, 390}, Interpolation: "BEZIER"},
+		{Frame: 100, Value: 30, HandleLeft: [2]float32{67, 30}, HandleRight: [2]float32{133, 30}, Interpolation: "BEZIER"},
+	}
+	for i, e := range expected {
+		k := keys[i]
+		degrees := func(v float32) float64 { return float64(v) * 180 / math.Pi }
+		if k.Frame != e.Frame || k.HandleLeft[0] != e.HandleLeft[0] || k.HandleRight[0] != e.HandleRight[0] ||
+			math.Abs(degrees(k.Value)-float64(e.Value)) > 0.001 ||
+			math.Abs(degrees(k.HandleLeft[1])-float64(e.HandleLeft[1])) > 0.001 ||
+			math.Abs(degrees(k.HandleRight[1])-float64(e.HandleRight[1])) > 0.001 ||
+			k.Interpolation != e.Interpolation {
+			t.Errorf("expected key %d to be %+v in degrees, got: %+v", i, e, k)
+		}
+	}
+
+	// baked F-Curves store sampled points instead
+	points := fx.add(CodeData, "FPoint", 3)
+	for i := 0; i < 3; i++ {
+		fx.set(points, i, "vec", []float32{float32(i + 1), float32(10 * i)})
+	}
+	fx.set(fcu, 0, "bezt", uint64(0))
+	fx.set(fcu, 0, "fpt", points.Header.OldMemoryAddress)
+	fx.set(fcu, 0, "totvert", 3)
+	keys, err = fx.file().FCurveKeyframes(fcu.Header.OldMemoryAddress)
+	if err != nil {
+		t.Fatalf("Expected nil error, got: %v", err)
+	}
+	if len(keys) != 3 {
+		t.Fatalf("expected 3 sampled keys, got: %v", keys)
+	}
+	for i, k := range keys {
+		point := [2]float32{float32(i + 1), float32(10 * i)}
+		if k.Frame != point[0] || k.Value != point[1] || k.HandleLeft != point || k.HandleRight != point || k.Interpolation != "LINEAR" {
+			t.Errorf("expected linear key at %v, got: %+v", point, k)
+		}
+	}
+}
+
+// rotationFCurve returns the block of the F-Curve animating the given axis of the cube's rotation.
+func rotationFCurve(t *testing.T, f *File, axis int) *Block {
+	t.Helper()
+	for _, b := range f.fileBlocks[CodeData] {
+		if !f.isStructured(b) || f.sdna.typeName(int(b.Header.SDNAIndex)) != "FCurve" {
+			continue
+		}
+		fcu, err := f.blockInstance(b, 0)
+		if err != nil {
+			t.Fatalf("Expected nil error, got: %v", err)
+		}
+		path, err := fcu.pointer("rna_path")
+		if err != nil {
+			t.Fatalf("Expected nil error, got: %v", err)
+		}
+		index, err := fcu.int("array_index")
+		if err != nil {
+			t.Fatalf("Expected nil error, got: %v", err)
+		}
+		if p, err := f.blockByAddress(path); err == nil && byteSliceToString(p.Data) == "rotation_euler" && int(index) == axis {
+			return b
+		}
+	}
+	t.Fatalf("expected F-Curve for rotation_euler[%d]", axis)
+	return nil
+}
+
+func TestFile_AnimData(t *testing.T) {
+	f := readExampleFile(t, "cubus-animated.blend")
+	addresses := map[string]uint64{}
+	for _, b := range f.fileBlocks[CodeObject] {
+		in, err := f.blockInstance(b, 0)
+		if err != nil {
+			t.Fatalf("Expected nil error, got: %v", err)
+		}
+		name, _ := in.idName()
+		addresses[name] = b.Header.OldMemoryAddress
+	}
+
+	data, err := f.AnimData(addresses["Cube"])
+	if err != nil {
+		t.Fatalf("Expected nil error, got: %v", err)
+	}
+	if data == nil || data.Action != "CubeAction" || len(data.NLATracks) != 0 || len(data.Drivers) != 0 {
+		t.Errorf("expected action 'CubeAction' only, got: %+v", data)
+	}
+	if data, err := f.AnimData(addresses["Camera"]); data != nil || err != nil {
+		t.Errorf("expected no animation data for the camera, got %+v with error: %v", data, err)
+	}
+}
+
+func TestFile_AnimDataDrivers(t *testing.T) {
+	fx := newFixture(t)
+	cube := fx.blockNamed(CodeObject, "OBCube")
+	camera := fx.blockNamed(CodeObject, "OBCamera")
+	ob, err := fx.f.blockInstance(cube, 0)
+	if err != nil {
+		t.Fatalf("Expected nil error, got: %v", err)
+	}
+	adtAddr, err := ob.pointer("adt")
+	if err != nil {
+		t.Fatalf("Expected nil error, got: %v", err)
+	}
+	adt := fx.f.addresses[adtAddr]
+
+	track := fx.add(CodeData, "NlaTrack", 1)
+	fx.set(track, 0, "name", "Walk")
+	fx.set(adt, 0, "nla_tracks.first", track.Header.OldMemoryAddress)
+
+	fcu := fx.add(CodeData, "FCurve", 1)
+	drivenPath := fx.addRaw(CodeData, 0, 1, []byte("location\x00"))
+	driver := fx.add(CodeData, "ChannelDriver", 1)
+	dvar := fx.add(CodeData, "DriverVar", 1)
+	targetPath := fx.addRaw(CodeData, 0, 1, []byte("location.x\x00"))
+	fx.set(fcu, 0, "rna_path", drivenPath.Header.OldMemoryAddress)
+	fx.set(fcu, 0, "array_index", 2)
+	fx.set(fcu, 0, "driver", driver.Header.OldMemoryAddress)
+	fx.set(driver, 0, "variables.first", dvar.Header.OldMemoryAddress)
+	fx.set(dvar, 0, "num_targets", 1)
+	v, err := fx.f.blockInstance(dvar, 0)
+	if err != nil {
+		t.Fatalf("Expected nil error, got: %v", err)
+	}
+	target, err := v.element("targets", 0)
+	if err != nil {
+		t.Fatalf("Expected nil error, got: %v", err)
+	}
+	for field, addr := range map[string]uint64{"id": camera.Header.OldMemoryAddress, "rna_path": targetPath.Header.OldMemoryAddress} {
+		_, data, err := target.field(field)
+		if err != nil {
+			t.Fatalf("Expected nil error, got: %v", err)
+		}
+		fx.f.order.PutUint64(data, addr)
+	}
+	fx.set(adt, 0, "drivers.first", fcu.Header.OldMemoryAddress)
+
+	data, err := fx.file().AnimData(cube.Header.OldMemoryAddress)
+	if err != nil {
+		t.Fatalf("Expected nil error, got: %v", err)
+	}
+	if len(data.NLATracks) != 1 || data.NLATracks[0] != "Walk" {
+		t.Errorf("expected NLA track 'Walk', got: %v", data.NLATracks)
+	}
+	expected := Driver{Path: "location", Index: 2, Targets: []DriverTarget{{ID: "Camera", Path: "location.x"}}}
+	if len(data.Drivers) != 1 || !reflect.DeepEqual(data.Drivers[0], expected) {
+		t.Errorf("expected driver %+v, got: %+v", expected, data.Drivers)
+	}
+}
@@ -0,0 +1,149 @@
+package blend
+
+import "fmt"
+
+// Armature is an armature datablock stored in a file-block with code 'AR' along with its bones.
+type Armature struct {
+	// Name of the datablock without its ID code
+	Name string
+	// Bones without a parent, each holding its children
+	Bones []Bone
+}
+
+// Bone is a single bone of an armature.
+type Bone struct {
+	// Name of the bone
+	Name string
+	// Position of the head of the bone in armature space
+	Head [3]float32
+	// Position of the tail of the bone in armature space
+	Tail [3]float32
+	// Name of the parent bone, empty for root bones
+	Parent string
+	// Bones parented to this bone
+	Children []Bone
+}
+
+// armatureBone is a bone along with the addresses linking it to its parent.
+type armatureBone struct {
+	addr   uint64
+	parent uint64
+	bone   Bone
+}
+
+// Armatures decodes all armatures of the file along with their bone hierarchies.
+// Files without armatures return an empty slice.
+func (f *File) Armatures() ([]Armature, error) {
+	armatures := []Armature{}
+	err := f.eachStruct(CodeArmature, func(ar *instance) error {
+		name, err := ar.idName()
+		if err != nil {
+			return err
+		}
+		first, err := ar.pointer("bonebase.first")
+		if err != nil {
+			return err
+		}
+		var bones []armatureBone
+		if err := f.readBones(first, make(map[uint64]bool), &bones); err != nil {
+			return fmt.Errorf("blend: unable to read bones of armature '%s': %w", name, err)
+		}
+		armatures = append(armatures, Armature{
+			Name:  name,
+			Bones: boneTree(bones),
+		})
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return armatures, nil
+}
+
+// readBones appends the bones of the list starting at first and of all their child lists to bones.
+// Blender only lists root bones in the bonebase of an armature, their children are found in the childbase of each bone.
+func (f *File) readBones(first uint64, seen map[uint64]bool, bones *[]armatureBone) error {
+	addr := first
+	return f.walkList(first, func(b *instance) error {
+		if seen[addr] {
+			return fmt.Errorf("blend: bone at address %#x is listed more than once", addr)
+		}
+		seen[addr] = true
+		bone, err := decodeBone(b)
+		if err != nil {
+			return err
+		}
+		parent, err := b.pointer("parent")
+		if err != nil {
+			return err
+		}
+		*bones = append(*bones, armatureBone{addr: addr, parent: parent, bone: bone})
+
+		children, err := b.pointer("childbase.first")
+		if err != nil {
+			return err
+		}
+		if err := f.readBones(children, seen, bones); err != nil {
+			return err
+		}
+		addr, err = b.next()
+		return err
+	})
+}
+
+// decodeBone decodes the name and armature space position of a bone.
+func decodeBone(b *instance) (Bone, error) {
+	name, err := b.string("name")
+	if err != nil {
+		return Bone{}, err
+	}
+	bone := Bone{Name: name}
+	for _, v := range []struct {
+		path string
+		dst  *[3]float32
+	}{
+		{"arm_head", &bone.Head},
+		{"arm_tail", &bone.Tail},
+	} {
+		_, data, err := b.field(v.path)
+		if err != nil {
+			return Bone{}, err
+		}
+		vectors := b.f.decodeVectors(data)
+		if len(vectors) == 0 {
+			return Bone{}, fmt.Errorf("blend: field '%s' of %s is not a vector", v.path, b.typeName())
+		}
+		*v.dst = vectors[0]
+	}
+	return bone, nil
+}
+
+// boneTree links bones to their parents and returns the root bones, each holding its children.
+// Bones with a parent that is not part of the armature are treated as roots.
+func boneTree(bones []armatureBone) []Bone {
+	known := make(map[uint64]bool, len(bones))
+	for _, b := range bones {
+		known[b.addr] = true
+	}
+	children := make(map[uint64][]armatureBone)
+	for _, b := range bones {
+		parent := b.parent
+		if !known[parent] {
+			parent = 0
+		}
+		children[parent] = append(children[parent], b)
+	}
+
+	var link func(parent uint64, parentName string) []Bone
+	link = func(parent uint64, parentName string) []Bone {
+		var tree []Bone
+		for _, b := range children[parent] {
+			bone := b.bone
+			bone.Parent = parentName
+			bone.Children = link(b.addr, bone.Name)
+			tree = append(tree, bone)
+		}
+		return tree
+	}
+	return link(0, "")
+}
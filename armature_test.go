@@ -0,0 +1,69 @@
+package blend
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestFile_Armatures(t *testing.T) {
+	fx := newFixture(t)
+	ar := fx.add(CodeArmature, "bArmature", 1)
+	fx.set(ar, 0, "id.name", "ARRig")
+	root := fx.add("DATA", "Bone", 1)
+	fx.set(root, 0, "name", "Spine")
+	fx.set(root, 0, "arm_tail", []float32{0, 0, 1})
+	child := fx.add("DATA", "Bone", 1)
+	fx.set(child, 0, "name", "Neck")
+	fx.set(child, 0, "arm_head", []float32{0, 0, 1})
+	fx.set(child, 0, "arm_tail", []float32{0, 0, 1.5})
+	fx.set(child, 0, "parent", root.Header.OldMemoryAddress)
+	fx.set(root, 0, "childbase.first", child.Header.OldMemoryAddress)
+	fx.set(root, 0, "childbase.last", child.Header.OldMemoryAddress)
+	fx.set(ar, 0, "bonebase.first", root.Header.OldMemoryAddress)
+	fx.set(ar, 0, "bonebase.last", root.Header.OldMemoryAddress)
+
+	armatures, err := fx.file().Armatures()
+	if err != nil {
+		t.Fatalf("Expected nil error, got: %v", err)
+	}
+	expected := []Armature{{
+		Name: "Rig",
+		Bones: []Bone{{
+			Name: "Spine",
+			Tail: [3]float32{0, 0, 1},
+			Children: []Bone{{
+				Name:   "Neck",
+				Head:   [3]float32{0, 0, 1},
+				Tail:   [3]float32{0, 0, 1.5},
+				Parent: "Spine",
+			}},
+		}},
+	}}
+	if !reflect.DeepEqual(armatures, expected) {
+		t.Errorf("expected %+v, got: %+v", expected, armatures)
+	}
+}
+
+func TestFile_ArmaturesCyclicBones(t *testing.T) {
+	fx := newFixture(t)
+	ar := fx.add(CodeArmature, "bArmature", 1)
+	bone := fx.add("DATA", "Bone", 1)
+	fx.set(bone, 0, "childbase.first", bone.Header.OldMemoryAddress)
+	fx.set(ar, 0, "bonebase.first", bone.Header.OldMemoryAddress)
+
+	if _, err := fx.file().Armatures(); err == nil {
+		t.Error("expected error for a bone listed as its own child")
+	}
+}
+
+func TestFile_ArmaturesNone(t *testing.T) {
+	f := readExampleFile(t, "cubus-animated.blend")
+
+	armatures, err := f.Armatures()
+	if err != nil {
+		t.Fatalf("Expected nil error, got: %v", err)
+	}
+	if armatures == nil || len(armatures) != 0 {
+		t.Errorf("expected no armatures, got: %v", armatures)
+	}
+}
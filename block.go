@@ -0,0 +1,36 @@
+package blend
+
+import "bytes"
+
+// EqualOption configures how blocks are compared by Block.Equal.
+type EqualOption func(*equalOptions)
+
+type equalOptions struct {
+	ignoreAddress bool
+}
+
+// IgnoreAddress makes Block.Equal disregard the old memory address of the blocks,
+// which differs between saves of otherwise identical files.
+func IgnoreAddress() EqualOption {
+	return func(o *equalOptions) {
+		o.ignoreAddress = true
+	}
+}
+
+// Equal reports whether b and other have the same header and data.
+// The position of the blocks within their files is not compared.
+func (b *Block) Equal(other *Block, opts ...EqualOption) bool {
+	if b == nil || other == nil {
+		return b == other
+	}
+	var o equalOptions
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	h, oh := b.Header, other.Header
+	if o.ignoreAddress {
+		h.OldMemoryAddress, oh.OldMemoryAddress = 0, 0
+	}
+	return h == oh && bytes.Equal(b.Data, other.Data)
+}
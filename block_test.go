@@ -0,0 +1,38 @@
+package blend
+
+import "testing"
+
+func TestBlock_Equal(t *testing.T) {
+	first := readExampleFile(t, "cubus-animated.blend")
+	second := readExampleFile(t, "cubus-animated.blend")
+
+	for i, b := range first.blocks {
+		if !b.Equal(second.blocks[i]) {
+			t.Errorf("expected block '%s' at index %d to equal its second read", b.Header.Code, i)
+		}
+	}
+
+	a, b := first.blocks[0], second.blocks[0]
+	b.Data = append([]byte{}, b.Data...)
+	b.Data[0]++
+	if a.Equal(b) {
+		t.Error("expected blocks with different data to be unequal")
+	}
+	if a.Equal(nil) || !(*Block)(nil).Equal(nil) {
+		t.Error("expected only nil to equal nil")
+	}
+}
+
+func TestBlock_EqualIgnoreAddress(t *testing.T) {
+	f := readExampleFile(t, "cubus-animated.blend")
+	a := f.blocks[0]
+	b := &Block{Header: a.Header, Data: a.Data}
+	b.Header.OldMemoryAddress++
+
+	if a.Equal(b) {
+		t.Error("expected blocks with different addresses to be unequal")
+	}
+	if !a.Equal(b, IgnoreAddress()) {
+		t.Error("expected blocks with different addresses to be equal when ignoring the address")
+	}
+}
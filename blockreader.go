@@ -0,0 +1,88 @@
+package blend
+
+import (
+	"bytes"
+	"errors"
+	"io"
+)
+
+// BlockReader reads file-blocks one at a time, leaving it to the consumer whether to keep them.
+type BlockReader struct {
+	f    *File
+	done bool
+}
+
+// BlockReader returns a reader which pulls the file-blocks following the header one at a time.
+// Blocks read through it are not cached, hence it can not be combined with methods reading all blocks of the File.
+// Reading is strictly sequential, so the File may read from a stream like the body of an HTTP response.
+func (f *File) BlockReader() *BlockReader {
+	return &BlockReader{f: f}
+}
+
+// Next reads the next file-block.
+// It returns io.EOF once the 'ENDB' block or the end of the file is reached.
+func (br *BlockReader) Next() (*Block, error) {
+	if br.done {
+		return nil, io.EOF
+	}
+	b, err := br.f.readBlock()
+	if err != nil {
+		if errors.Is(err, io.EOF) {
+			br.done = true
+		}
+		return nil, err
+	}
+	if b.Header.Code == CodeEnd {
+		br.done = true
+		return nil, io.EOF
+	}
+	return b, nil
+}
+
+// ResyncTo skips ahead to the next block header with one of the given codes, which allows reading on
+// after Next returned an error for a damaged block, e.g. ErrBlockDesync under WithStrictValidation.
+// The header is only recognized by its code, so data happening to contain the code is taken for a header.
+// It returns io.EOF if none of the codes is found before the end of the file.
+func (br *BlockReader) ResyncTo(codes ...Code) error {
+	if len(codes) == 0 {
+		return errors.New("blend: no codes to resync to")
+	}
+	targets := make([][4]byte, len(codes))
+	for i, code := range codes {
+		copy(targets[i][:], code)
+	}
+
+	counter := br.f.counter
+	chunk := make([]byte, 4096)
+	var carry []byte
+	for {
+		n, err := counter.Read(chunk)
+		data := append(carry, chunk[:n]...)
+		for i := 0; i+4 <= len(data); i++ {
+			for _, target := range targets {
+				if !bytes.Equal(data[i:i+4], target[:]) {
+					continue
+				}
+				// hand the bytes read beyond the code back to the following reads
+				replay := append([]byte(nil), data[i:]...)
+				counter.r = io.MultiReader(bytes.NewReader(replay), counter.r)
+				counter.n -= int64(len(replay))
+				br.done = false
+				return nil
+			}
+		}
+		// a code may span two chunks
+		if len(data) > 3 {
+			data = data[len(data)-3:]
+		}
+		carry = append([]byte(nil), data...)
+
+		if err == io.EOF {
+			br.done = true
+			return io.EOF
+		}
+		if err != nil {
+			return err
+		}
+	}
+}
@@ -0,0 +1,171 @@
+package blend
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+)
+
+func TestBlockReader_Next(t *testing.T) {
+	eager := readExampleFile(t, "cubus-animated.blend")
+
+	name := "cubus-animated.blend"
+	r, err := readExample(name)
+	if err != nil {
+		t.Fatalf("Unable to read example file '%s': %s", name, err)
+	}
+	defer r.Close()
+
+	f, err := NewFile(r)
+	if err != nil {
+		t.Fatalf("Expected nil error, got: %v", err)
+	}
+	br := f.BlockReader()
+
+	var blocks []*Block
+	for {
+		b, err := br.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatalf("Expected nil error, got: %v", err)
+		}
+		blocks = append(blocks, b)
+	}
+	if _, err := br.Next(); err != io.EOF {
+		t.Errorf("expected io.EOF after the last block, got: %v", err)
+	}
+
+	// the eager read additionally contains the terminating ENDB block
+	if len(blocks) != len(eager.blocks)-1 {
+		t.Fatalf("expected %d blocks, got %d", len(eager.blocks)-1, len(blocks))
+	}
+	for i, b := range blocks {
+		e := eager.blocks[i]
+		if b.Header != e.Header {
+			t.Errorf("expected header %+v at index %d, got: %+v", e.Header, i, b.Header)
+		}
+		if !bytes.Equal(b.Data, e.Data) {
+			t.Errorf("expected equal data for block %q at index %d", e.Header.Code, i)
+		}
+	}
+}
+
+func TestBlockReader_httpStream(t *testing.T) {
+	eager := readExampleFile(t, "cubus-animated.blend")
+	data, err := ioutil.ReadFile(filepath.Join("./examples", "cubus-animated.blend"))
+	if err != nil {
+		t.Fatalf("Expected nil error, got: %v", err)
+	}
+	// serve the file in small flushed chunks, so the body arrives chunked and reads return partially
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		for chunk := data; len(chunk) > 0; {
+			n := 1000
+			if n > len(chunk) {
+				n = len(chunk)
+			}
+			w.Write(chunk[:n])
+			w.(http.Flusher).Flush()
+			chunk = chunk[n:]
+		}
+	}))
+	defer server.Close()
+
+	resp, err := http.Get(server.URL)
+	if err != nil {
+		t.Fatalf("Expected nil error, got: %v", err)
+	}
+	defer resp.Body.Close()
+	if len(resp.TransferEncoding) == 0 || resp.TransferEncoding[0] != "chunked" {
+		t.Fatalf("expected chunked response, got transfer encoding %v", resp.TransferEncoding)
+	}
+
+	f, err := NewFile(resp.Body)
+	if err != nil {
+		t.Fatalf("Expected nil error, got: %v", err)
+	}
+	br := f.BlockReader()
+	n := 0
+	for ; ; n++ {
+		b, err := br.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatalf("Expected nil error, got: %v", err)
+		}
+		e := eager.blocks[n]
+		if b.Header != e.Header || !bytes.Equal(b.Data, e.Data) {
+			t.Errorf("expected block %q at index %d to equal the one read from disk", e.Header.Code, n)
+		}
+	}
+	if n != len(eager.blocks)-1 {
+		t.Errorf("expected %d blocks, got %d", len(eager.blocks)-1, n)
+	}
+}
+
+func TestBlockReader_ResyncTo(t *testing.T) {
+	eager := readExampleFile(t, "cubus-animated.blend")
+	fx := newFixture(t)
+	bad := fx.blockNamed("OB", "OBCube")
+	bad.Header.Size++
+
+	f, err := NewFile(bytes.NewReader(fx.bytes()), WithStrictValidation())
+	if err != nil {
+		t.Fatalf("Expected nil error, got: %v", err)
+	}
+	br := f.BlockReader()
+	for {
+		_, err := br.Next()
+		if errors.Is(err, ErrBlockDesync) {
+			break
+		}
+		if err != nil {
+			t.Fatalf("expected ErrBlockDesync, got: %v", err)
+		}
+	}
+	if err := br.ResyncTo(CodeObject, CodeDNA1); err != nil {
+		t.Fatalf("Expected nil error, got: %v", err)
+	}
+
+	// the header following the damaged block was consumed, so reading resumes with the next object or the SDNA
+	var tail []*Block
+	for i, b := range eager.blocks {
+		if b.Header.OldMemoryAddress == bad.Header.OldMemoryAddress {
+			for _, b := range eager.blocks[i+2 : len(eager.blocks)-1] {
+				if len(tail) > 0 || b.Header.Code == CodeObject || b.Header.Code == CodeDNA1 {
+					tail = append(tail, b)
+				}
+			}
+		}
+	}
+	n := 0
+	for ; ; n++ {
+		b, err := br.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatalf("Expected nil error, got: %v", err)
+		}
+		if n >= len(tail) {
+			continue
+		}
+		if b.Header != tail[n].Header || !bytes.Equal(b.Data, tail[n].Data) {
+			t.Errorf("expected block %q at index %d of the tail to equal the one read from disk", tail[n].Header.Code, n)
+		}
+	}
+	if n != len(tail) || n == 0 {
+		t.Errorf("expected %d blocks after resync, got %d", len(tail), n)
+	}
+
+	if err := br.ResyncTo(CodeObject); err != io.EOF {
+		t.Errorf("expected io.EOF resyncing at the end of the file, got: %v", err)
+	}
+}
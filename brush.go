@@ -0,0 +1,40 @@
+package blend
+
+// Brush is a brush datablock used by the painting and sculpting tools.
+type Brush struct {
+	// Name of the datablock without its ID code
+	Name string
+	// Radius of the brush in pixels
+	Size int
+	// Strength of the brush, which Blender stores in the `alpha` field
+	Strength float32
+}
+
+// Brushes decodes all brush datablocks of the file.
+func (f *File) Brushes() ([]Brush, error) {
+	var brushes []Brush
+	err := f.eachStruct(CodeBrush, func(br *instance) error {
+		name, err := br.idName()
+		if err != nil {
+			return err
+		}
+		size, err := br.int("size")
+		if err != nil {
+			return err
+		}
+		strength, err := br.float("alpha")
+		if err != nil {
+			return err
+		}
+		brushes = append(brushes, Brush{
+			Name:     name,
+			Size:     int(size),
+			Strength: float32(strength),
+		})
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return brushes, nil
+}
@@ -0,0 +1,32 @@
+package blend
+
+import "testing"
+
+func TestFile_Brushes(t *testing.T) {
+	f := readExampleFile(t, "cubus-animated.blend")
+
+	brushes, err := f.Brushes()
+	if err != nil {
+		t.Fatalf("Expected nil error, got: %v", err)
+	}
+	if len(brushes) != 43 {
+		t.Errorf("expected 43 brushes, got %d", len(brushes))
+	}
+
+	expected := map[string]Brush{
+		"Draw":  {Name: "Draw", Size: 35, Strength: 1},
+		"Clone": {Name: "Clone", Size: 35, Strength: 0.7},
+		"Grab":  {Name: "Grab", Size: 75, Strength: 1},
+	}
+	for _, b := range brushes {
+		if e, ok := expected[b.Name]; ok {
+			if b != e {
+				t.Errorf("expected %+v, got %+v", e, b)
+			}
+			delete(expected, b.Name)
+		}
+	}
+	for name := range expected {
+		t.Errorf("expected brush %q to be enumerated", name)
+	}
+}
@@ -0,0 +1,34 @@
+package blend
+
+// Code identifies the kind of data stored in a file-block, e.g. "OB" for objects.
+// Blocks holding ID datablocks use the two character ID code of their type.
+type Code string
+
+// Codes of the file-blocks written by Blender.
+const (
+	CodeRender           Code = "REND"
+	CodeTest             Code = "TEST"
+	CodeGlobal           Code = "GLOB"
+	CodeWindowManager    Code = "WM"
+	CodeWorkSpace        Code = "WS"
+	CodeScreen           Code = "SN"
+	CodeScene            Code = "SC"
+	CodeObject           Code = "OB"
+	CodeMesh             Code = "ME"
+	CodeCamera           Code = "CA"
+	CodeLight            Code = "LA"
+	CodeMaterial         Code = "MA"
+	CodeImage            Code = "IM"
+	CodeBrush            Code = "BR"
+	CodeAction           Code = "AC"
+	CodeCollection       Code = "GR"
+	CodeWorld            Code = "WO"
+	CodeLineStyle        Code = "LS"
+	CodeText             Code = "TX"
+	CodeParticleSettings Code = "PA"
+	CodeArmature         Code = "AR"
+	CodeNodeTree         Code = "NT"
+	CodeData             Code = "DATA"
+	CodeDNA1             Code = "DNA1"
+	CodeEnd              Code = "ENDB"
+)
@@ -0,0 +1,27 @@
+package blend
+
+import "testing"
+
+func TestCode_example(t *testing.T) {
+	f := readExampleFile(t, "cubus-animated.blend")
+
+	codes := []Code{
+		CodeRender, CodeTest, CodeGlobal, CodeWindowManager, CodeWorkSpace, CodeScreen, CodeScene,
+		CodeObject, CodeMesh, CodeCamera, CodeLight, CodeMaterial, CodeImage, CodeBrush, CodeAction,
+		CodeCollection, CodeWorld, CodeLineStyle, CodeData, CodeDNA1, CodeEnd,
+	}
+	// codes of blocks the example does not contain
+	for _, code := range []Code{CodeText, CodeParticleSettings, CodeArmature, CodeNodeTree} {
+		if _, ok := f.fileBlocks[code]; ok {
+			t.Errorf("expected no block with code '%s' in the example", code)
+		}
+	}
+	if len(codes) != len(f.fileBlocks) {
+		t.Errorf("expected %d codes, the example contains %d", len(codes), len(f.fileBlocks))
+	}
+	for _, code := range codes {
+		if _, ok := f.fileBlocks[code]; !ok {
+			t.Errorf("expected block with code '%s' in the example", code)
+		}
+	}
+}
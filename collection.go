@@ -0,0 +1,117 @@
+package blend
+
+import "fmt"
+
+// maxCollectionDepth bounds the nesting of collections, which is far deeper than any hierarchy built by hand.
+const maxCollectionDepth = 64
+
+// CollectionNode is a collection of a scene along with the collections nested in it.
+type CollectionNode struct {
+	// Name of the collection without its ID code, "Master Collection" for the collection of the scene itself
+	Name string
+	// Address of the collection
+	Address uint64
+	// Names of the objects directly linked to the collection, without their ID code
+	Objects []string
+	// Collections nested in the collection in the order of the outliner
+	Children []*CollectionNode
+}
+
+// SceneCollectionTree returns the collection hierarchy of the scene located at sceneAddr,
+// starting at the master collection of the scene. A collection linked several times is decoded once,
+// with each parent referencing the same node.
+// Files saved before Blender 2.80 have no collections and fail.
+func (f *File) SceneCollectionTree(sceneAddr uint64) (*CollectionNode, error) {
+	sc, err := f.structAt(sceneAddr, "Scene")
+	if err != nil {
+		return nil, err
+	}
+	if !sc.hasField("master_collection") {
+		return nil, fmt.Errorf("blend: scene at %#x has no collections", sceneAddr)
+	}
+	master, err := sc.pointer("master_collection")
+	if err != nil {
+		return nil, err
+	}
+	if master == 0 {
+		return nil, fmt.Errorf("blend: scene at %#x has no master collection", sceneAddr)
+	}
+	return f.collectionNode(master, 0, make(map[uint64]*CollectionNode), make(map[uint64]bool))
+}
+
+// collectionNode reads the collection located at addr and the collections nested in it,
+// the collection itself being nested in depth others. Collections already read are taken from nodes,
+// and path holds the collections the collection is nested in, which it must not contain.
+func (f *File) collectionNode(addr uint64, depth int, nodes map[uint64]*CollectionNode, path map[uint64]bool) (*CollectionNode, error) {
+	if depth > maxCollectionDepth {
+		return nil, fmt.Errorf("blend: collections nested deeper than %d levels at %#x", maxCollectionDepth, addr)
+	}
+	if path[addr] {
+		return nil, fmt.Errorf("blend: collection at %#x contains itself", addr)
+	}
+	if node, ok := nodes[addr]; ok {
+		return node, nil
+	}
+	path[addr] = true
+	defer delete(path, addr)
+	gr, err := f.structAt(addr, "Collection")
+	if err != nil {
+		return nil, err
+	}
+	name, err := gr.idName()
+	if err != nil {
+		return nil, err
+	}
+	node := &CollectionNode{
+		Name:     name,
+		Address:  addr,
+		Objects:  []string{},
+		Children: []*CollectionNode{},
+	}
+
+	first, err := gr.pointer("gobject.first")
+	if err != nil {
+		return nil, err
+	}
+	err = f.walkList(first, func(cob *instance) error {
+		ob, err := cob.pointer("ob")
+		if err != nil || ob == 0 {
+			return err
+		}
+		o, err := f.structAt(ob, "Object")
+		if err != nil {
+			return err
+		}
+		name, err := o.idName()
+		if err != nil {
+			return err
+		}
+		node.Objects = append(node.Objects, name)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	first, err = gr.pointer("children.first")
+	if err != nil {
+		return nil, err
+	}
+	err = f.walkList(first, func(child *instance) error {
+		collection, err := child.pointer("collection")
+		if err != nil || collection == 0 {
+			return err
+		}
+		c, err := f.collectionNode(collection, depth+1, nodes, path)
+		if err != nil {
+			return err
+		}
+		node.Children = append(node.Children, c)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	nodes[addr] = node
+	return node, nil
+}
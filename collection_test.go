@@ -0,0 +1,87 @@
+package blend
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestFile_SceneCollectionTree(t *testing.T) {
+	f := readExampleFile(t, "cubus-animated.blend")
+
+	root, err := f.SceneCollectionTree(f.fileBlocks[CodeScene][0].Header.OldMemoryAddress)
+	if err != nil {
+		t.Fatalf("Expected nil error, got: %v", err)
+	}
+	if root.Name != "Master Collection" || len(root.Objects) != 0 || len(root.Children) != 1 {
+		t.Fatalf("expected master collection holding a single collection, got: %+v", root)
+	}
+	child := root.Children[0]
+	if child.Name != "Collection" || child.Address != f.fileBlocks[CodeCollection][0].Header.OldMemoryAddress {
+		t.Errorf("expected child 'Collection', got: %+v", child)
+	}
+	if strings.Join(child.Objects, ",") != "Cube,Light,Camera" {
+		t.Errorf("expected objects Cube, Light and Camera, got: %v", child.Objects)
+	}
+}
+
+func TestFile_SceneCollectionTreeCycle(t *testing.T) {
+	fx := newFixture(t)
+	scene := fx.block(CodeScene, 0)
+	sc, err := fx.f.blockInstance(scene, 0)
+	if err != nil {
+		t.Fatalf("Expected nil error, got: %v", err)
+	}
+	master, err := sc.pointer("master_collection")
+	if err != nil {
+		t.Fatalf("Expected nil error, got: %v", err)
+	}
+	gr, err := fx.f.structAt(master, "Collection")
+	if err != nil {
+		t.Fatalf("Expected nil error, got: %v", err)
+	}
+	first, err := gr.pointer("children.first")
+	if err != nil {
+		t.Fatalf("Expected nil error, got: %v", err)
+	}
+	// let the master collection contain itself
+	fx.set(fx.f.addresses[first], 0, "collection", master)
+
+	_, err = fx.file().SceneCollectionTree(scene.Header.OldMemoryAddress)
+	if err == nil || !strings.Contains(err.Error(), "contains itself") {
+		t.Errorf("expected error for a collection containing itself, got: %v", err)
+	}
+}
+
+func TestFile_SceneCollectionTreeShared(t *testing.T) {
+	fx := newFixture(t)
+	scene := fx.block(CodeScene, 0)
+	collection := fx.block(CodeCollection, 0).Header.OldMemoryAddress
+	sc, err := fx.f.blockInstance(scene, 0)
+	if err != nil {
+		t.Fatalf("Expected nil error, got: %v", err)
+	}
+	master, err := sc.pointer("master_collection")
+	if err != nil {
+		t.Fatalf("Expected nil error, got: %v", err)
+	}
+	gr, err := fx.f.structAt(master, "Collection")
+	if err != nil {
+		t.Fatalf("Expected nil error, got: %v", err)
+	}
+	first, err := gr.pointer("children.first")
+	if err != nil {
+		t.Fatalf("Expected nil error, got: %v", err)
+	}
+	// link the collection to the master collection a second time
+	second := fx.add(CodeData, "CollectionChild", 1)
+	fx.set(second, 0, "collection", collection)
+	fx.set(fx.f.addresses[first], 0, "next", second.Header.OldMemoryAddress)
+
+	root, err := fx.file().SceneCollectionTree(scene.Header.OldMemoryAddress)
+	if err != nil {
+		t.Fatalf("Expected nil error, got: %v", err)
+	}
+	if len(root.Children) != 2 || root.Children[0] != root.Children[1] {
+		t.Errorf("expected both links to share the node of the collection, got: %+v", root.Children)
+	}
+}
@@ -0,0 +1,113 @@
+package blend
+
+import "fmt"
+
+// Constraint is a single entry of the constraint stack of an object.
+type Constraint struct {
+	// Raw value of the `type` field
+	Type int
+	// Name of the constraint type as used by the Python API, e.g. TRACK_TO
+	TypeName string
+	// Name of the constraint shown in the user interface
+	Name string
+	// Address of the target object, 0 if the constraint has none or targets several objects
+	Target uint64
+}
+
+// constraintTypeNames maps the values of Blender's eBConstraint_Types enum to the names used by the Python API.
+var constraintTypeNames = map[int]string{
+	1:  "CHILD_OF",
+	2:  "TRACK_TO",
+	3:  "IK",
+	4:  "FOLLOW_PATH",
+	5:  "LIMIT_ROTATION",
+	6:  "LIMIT_LOCATION",
+	7:  "LIMIT_SCALE",
+	8:  "COPY_ROTATION",
+	9:  "COPY_LOCATION",
+	10: "COPY_SCALE",
+	11: "PYTHON",
+	12: "ACTION",
+	13: "LOCKED_TRACK",
+	14: "LIMIT_DISTANCE",
+	15: "STRETCH_TO",
+	16: "FLOOR",
+	18: "CLAMP_TO",
+	19: "TRANSFORM",
+	20: "SHRINKWRAP",
+	21: "DAMPED_TRACK",
+	22: "SPLINE_IK",
+	23: "COPY_TRANSFORMS",
+	24: "MAINTAIN_VOLUME",
+	25: "PIVOT",
+	26: "FOLLOW_TRACK",
+	27: "CAMERA_SOLVER",
+	28: "OBJECT_SOLVER",
+	29: "TRANSFORM_CACHE",
+	30: "ARMATURE",
+}
+
+// ConstraintTypeName returns the name of a constraint's `type`, e.g. TRACK_TO, or "UNKNOWN".
+func ConstraintTypeName(t int) string {
+	if name, ok := constraintTypeNames[t]; ok {
+		return name
+	}
+	return "UNKNOWN"
+}
+
+// Constraints decodes the constraint stack of the object located at objectAddr in evaluation order.
+// Objects without constraints return an empty slice.
+func (f *File) Constraints(objectAddr uint64) ([]Constraint, error) {
+	ob, err := f.structAt(objectAddr, "Object")
+	if err != nil {
+		return nil, err
+	}
+	first, err := ob.pointer("constraints.first")
+	if err != nil {
+		return nil, err
+	}
+
+	constraints := []Constraint{}
+	err = f.walkList(first, func(con *instance) error {
+		t, err := con.int("type")
+		if err != nil {
+			return err
+		}
+		name, err := con.string("name")
+		if err != nil {
+			return err
+		}
+		target, err := f.constraintTarget(con)
+		if err != nil {
+			return err
+		}
+		constraints = append(constraints, Constraint{
+			Type:     int(t),
+			TypeName: ConstraintTypeName(int(t)),
+			Name:     name,
+			Target:   target,
+		})
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("blend: unable to read constraints of object at %#x: %w", objectAddr, err)
+	}
+	return constraints, nil
+}
+
+// constraintTarget reads the target object of a constraint from the `tar` field of its type specific data,
+// which constraints with a single target have in common.
+func (f *File) constraintTarget(con *instance) (uint64, error) {
+	data, err := con.pointer("data")
+	if err != nil || data == 0 {
+		return 0, err
+	}
+	in, err := f.instanceAt(data)
+	if err != nil {
+		return 0, err
+	}
+	if !in.hasField("tar") {
+		return 0, nil
+	}
+	return in.pointer("tar")
+}
@@ -0,0 +1,59 @@
+package blend
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestConstraintTypeName(t *testing.T) {
+	if n := ConstraintTypeName(2); n != "TRACK_TO" {
+		t.Errorf("expected TRACK_TO, got %q", n)
+	}
+	if n := ConstraintTypeName(99); n != "UNKNOWN" {
+		t.Errorf("expected UNKNOWN, got %q", n)
+	}
+}
+
+func TestFile_Constraints(t *testing.T) {
+	fx := newFixture(t)
+	ob := fx.blockNamed("OB", "OBCamera")
+	cube := fx.blockNamed("OB", "OBCube").Header.OldMemoryAddress
+	data := fx.add("DATA", "bTrackToConstraint", 1)
+	fx.set(data, 0, "tar", cube)
+	trackTo := fx.add("DATA", "bConstraint", 1)
+	fx.set(trackTo, 0, "type", 2)
+	fx.set(trackTo, 0, "name", "Track To")
+	fx.set(trackTo, 0, "data", data.Header.OldMemoryAddress)
+	limit := fx.add("DATA", "bConstraint", 1)
+	fx.set(limit, 0, "type", 6)
+	fx.set(limit, 0, "name", "Limit Location")
+	fx.set(trackTo, 0, "next", limit.Header.OldMemoryAddress)
+	fx.set(ob, 0, "constraints.first", trackTo.Header.OldMemoryAddress)
+	fx.set(ob, 0, "constraints.last", limit.Header.OldMemoryAddress)
+
+	constraints, err := fx.file().Constraints(ob.Header.OldMemoryAddress)
+	if err != nil {
+		t.Fatalf("Expected nil error, got: %v", err)
+	}
+	expected := []Constraint{
+		{Type: 2, TypeName: "TRACK_TO", Name: "Track To", Target: cube},
+		{Type: 6, TypeName: "LIMIT_LOCATION", Name: "Limit Location"},
+	}
+	if !reflect.DeepEqual(constraints, expected) {
+		t.Errorf("expected %+v, got: %+v", expected, constraints)
+	}
+}
+
+func TestFile_ConstraintsEmpty(t *testing.T) {
+	f := readExampleFile(t, "cubus-animated.blend")
+
+	for _, b := range f.fileBlocks["OB"] {
+		constraints, err := f.Constraints(b.Header.OldMemoryAddress)
+		if err != nil {
+			t.Fatalf("Expected nil error, got: %v", err)
+		}
+		if constraints == nil || len(constraints) != 0 {
+			t.Errorf("expected empty constraint stack, got: %v", constraints)
+		}
+	}
+}
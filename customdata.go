@@ -0,0 +1,178 @@
+package blend
+
+import "fmt"
+
+// CustomDataLayer is a layer of the CustomData of a mesh, holding one value per element of its domain.
+type CustomDataLayer struct {
+	// Raw value of the `type` field
+	Type int
+	// Name of the layer type as in Blender's CustomData types without the CD_ prefix, e.g. MLOOPUV
+	TypeName string
+	// Name of the layer, e.g. "UVMap", which is empty for most layers that are not attributes
+	Name string
+	// Address of the block holding the data of the layer
+	Data uint64
+}
+
+// customDataTypeNames maps the values of Blender's CustomData types to their names without the CD_ prefix.
+var customDataTypeNames = map[int]string{
+	0:  "MVERT",
+	1:  "MSTICKY",
+	2:  "MDEFORMVERT",
+	3:  "MEDGE",
+	4:  "MFACE",
+	5:  "MTFACE",
+	6:  "MCOL",
+	7:  "ORIGINDEX",
+	8:  "NORMAL",
+	9:  "FACEMAP",
+	10: "PROP_FLOAT",
+	11: "PROP_INT32",
+	12: "PROP_STRING",
+	13: "ORIGSPACE",
+	14: "ORCO",
+	15: "MTEXPOLY",
+	16: "MLOOPUV",
+	17: "PROP_BYTE_COLOR",
+	18: "TANGENT",
+	19: "MDISPS",
+	20: "PREVIEW_MCOL",
+	21: "ID_MCOL",
+	22: "TEXTURE_MLOOPCOL",
+	23: "CLOTH_ORCO",
+	24: "RECAST",
+	25: "MPOLY",
+	26: "MLOOP",
+	27: "SHAPE_KEYINDEX",
+	28: "SHAPEKEY",
+	29: "BWEIGHT",
+	30: "CREASE",
+	31: "ORIGSPACE_MLOOP",
+	32: "PREVIEW_MLOOPCOL",
+	33: "BM_ELEM_PYPTR",
+	34: "PAINT_MASK",
+	35: "GRID_PAINT_MASK",
+	36: "MVERT_SKIN",
+	37: "FREESTYLE_EDGE",
+	38: "FREESTYLE_FACE",
+	39: "MLOOPTANGENT",
+	40: "TESSLOOPNORMAL",
+	41: "CUSTOMLOOPNORMAL",
+	42: "SCULPT_FACE_SETS",
+	43: "LOCATION",
+	44: "RADIUS",
+	45: "PROP_INT8",
+	46: "HAIRMAPPING",
+	47: "PROP_COLOR",
+	48: "PROP_FLOAT3",
+	49: "PROP_FLOAT2",
+	50: "PROP_BOOL",
+	51: "HAIRLENGTH",
+	52: "PROP_QUATERNION",
+}
+
+// CustomDataTypeName returns the name of a CustomData layer type, e.g. MLOOPUV, or "UNKNOWN".
+func CustomDataTypeName(t int) string {
+	if name, ok := customDataTypeNames[t]; ok {
+		return name
+	}
+	return "UNKNOWN"
+}
+
+// customDataDomains maps the domains accepted by CustomDataLayers to the CustomData fields of a Mesh.
+var customDataDomains = map[string]string{
+	"vert": "vdata",
+	"edge": "edata",
+	"loop": "ldata",
+	"poly": "pdata",
+}
+
+// CustomDataLayers returns the layers of the mesh located at meshAddr for the given domain,
+// which is one of "vert", "edge", "loop" and "poly", in the order they are stored.
+func (f *File) CustomDataLayers(meshAddr uint64, domain string) ([]CustomDataLayer, error) {
+	path, ok := customDataDomains[domain]
+	if !ok {
+		return nil, fmt.Errorf("blend: unknown custom data domain '%s'", domain)
+	}
+	me, err := f.structAt(meshAddr, "Mesh")
+	if err != nil {
+		return nil, err
+	}
+	layers, err := f.allCustomDataLayers(me, path)
+	if err != nil {
+		return nil, err
+	}
+	result := make([]CustomDataLayer, 0, len(layers))
+	for _, l := range layers {
+		t, err := l.int("type")
+		if err != nil {
+			return nil, err
+		}
+		name, err := l.string("name")
+		if err != nil {
+			return nil, err
+		}
+		data, err := l.pointer("data")
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, CustomDataLayer{
+			Type:     int(t),
+			TypeName: CustomDataTypeName(int(t)),
+			Name:     name,
+			Data:     data,
+		})
+	}
+	return result, nil
+}
+
+// customDataLayers returns the layers of the given type of the CustomData at path of in, e.g. "ldata" of a Mesh.
+func (f *File) customDataLayers(in *instance, path string, layerType int) ([]*instance, error) {
+	all, err := f.allCustomDataLayers(in, path)
+	if err != nil {
+		return nil, err
+	}
+	var layers []*instance
+	for _, l := range all {
+		t, err := l.int("type")
+		if err != nil {
+			return nil, err
+		}
+		if int(t) == layerType {
+			layers = append(layers, l)
+		}
+	}
+	return layers, nil
+}
+
+// allCustomDataLayers returns all layers of the CustomData at path of in.
+func (f *File) allCustomDataLayers(in *instance, path string) ([]*instance, error) {
+	addr, err := in.pointer(path + ".layers")
+	if err != nil {
+		return nil, err
+	}
+	total, err := in.int(path + ".totlayer")
+	if err != nil {
+		return nil, err
+	}
+	if addr == 0 || total <= 0 {
+		return nil, nil
+	}
+	b, err := f.blockByAddress(addr)
+	if err != nil {
+		return nil, err
+	}
+	if err := f.checkInstances(b, int(total)); err != nil {
+		return nil, err
+	}
+
+	layers := make([]*instance, 0, total)
+	for i := 0; i < int(total); i++ {
+		l, err := f.blockInstance(b, i)
+		if err != nil {
+			return nil, err
+		}
+		layers = append(layers, l)
+	}
+	return layers, nil
+}
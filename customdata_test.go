@@ -0,0 +1,53 @@
+package blend
+
+import "testing"
+
+func TestFile_CustomDataLayers(t *testing.T) {
+	f := readExampleFile(t, "cubus-animated.blend")
+	me := f.fileBlocks[CodeMesh][0].Header.OldMemoryAddress
+
+	layers, err := f.CustomDataLayers(me, "loop")
+	if err != nil {
+		t.Fatalf("Expected nil error, got: %v", err)
+	}
+	if len(layers) != 2 {
+		t.Fatalf("expected 2 loop layers, got: %+v", layers)
+	}
+	uv := layers[0]
+	if uv.Type != cdMLoopUV || uv.TypeName != "MLOOPUV" || uv.Name != "UVMap" {
+		t.Errorf("expected uv map 'UVMap', got: %+v", uv)
+	}
+	if b, ok := f.addresses[uv.Data]; !ok || b.Header.Count != 24 {
+		t.Errorf("expected uv map data holding 24 coordinates at %#x", uv.Data)
+	}
+	if layers[1].TypeName != "MLOOP" {
+		t.Errorf("expected loop layer MLOOP, got %s", layers[1].TypeName)
+	}
+
+	for domain, typeName := range map[string]string{"vert": "MVERT", "edge": "MEDGE", "poly": "MPOLY"} {
+		layers, err := f.CustomDataLayers(me, domain)
+		if err != nil {
+			t.Fatalf("Expected nil error, got: %v", err)
+		}
+		if len(layers) == 0 || layers[0].TypeName != typeName {
+			t.Errorf("expected first %s layer %s, got: %+v", domain, typeName, layers)
+		}
+	}
+
+	if _, err := f.CustomDataLayers(me, "face"); err == nil {
+		t.Error("expected error for unknown domain")
+	}
+}
+
+func TestCustomDataTypeName(t *testing.T) {
+	testTable := map[int]string{
+		16:  "MLOOPUV",
+		41:  "CUSTOMLOOPNORMAL",
+		999: "UNKNOWN",
+	}
+	for value, expected := range testTable {
+		if name := CustomDataTypeName(value); name != expected {
+			t.Errorf("expected CustomDataTypeName(%d) to be %s, got %s", value, expected, name)
+		}
+	}
+}
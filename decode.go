@@ -0,0 +1,357 @@
+package blend
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+	"strings"
+)
+
+// DecodeBlock decodes every structure stored in the first file-block with the given code.
+// Each structure is returned as a map of field names to values: embedded structures decode to nested maps,
+// pointers to the address they pointed to and char arrays to strings. Arrays of numbers and pointers decode
+// to typed slices, e.g. []float32 for "loc[3]", nested for each further dimension like [][]float32 for
+// "obmat[4][4]", with multidimensional char arrays decoding to slices of strings.
+// Arrays of structures and of types unknown to the decoder decode to their raw bytes.
+// Fields with a registered enum decode to an EnumValue.
+// Padding fields like "_pad0" are omitted unless WithIncludePadding is given.
+// A block holding raw data rather than structures, see IsStructured, decodes to a single map
+// holding its data under RawKey.
+// Unless disabled by WithDecodeCache, the result is cached, and every call returns a copy which may be modified.
+func (f *File) DecodeBlock(code Code) ([]map[string]interface{}, error) {
+	if _, err := f.SDNA(); err != nil {
+		return nil, err
+	}
+	blocks, ok := f.fileBlocks[code]
+	if !ok {
+		return nil, fmt.Errorf("file block '%s' not found", code)
+	}
+	return f.decodeBlock(blocks[0])
+}
+
+// RawKey is the key under which DecodeBlock returns the data of a block not holding structures.
+// It can not collide with a field name as SDNA identifiers never contain '$'.
+const RawKey = "$raw"
+
+// IsStructured reports whether all file-blocks with the given code hold structures described by the SDNA,
+// as opposed to raw data like the float arrays and pointer lists Blender writes into 'DATA' blocks,
+// whose type depends on the structure referencing them. It returns false if there is no such block
+// or the file-blocks can not be read.
+func (f *File) IsStructured(code Code) bool {
+	if err := f.loadBlocks(); err != nil {
+		return false
+	}
+	blocks, ok := f.fileBlocks[code]
+	if !ok {
+		return false
+	}
+	for _, b := range blocks {
+		if !f.isStructured(b) {
+			return false
+		}
+	}
+	return true
+}
+
+// isStructured reports whether b holds structures of the type its SDNA index refers to.
+// Blender writes raw data with SDNA index 0, like the RenderInfo of 'REND' and the thumbnail of 'TEST',
+// so such blocks never hold the structure at index 0. A block referencing an unknown index is left to fail decoding.
+func (f *File) isStructured(b *Block) bool {
+	return b.Header.SDNAIndex != 0
+}
+
+// FieldBytes returns the raw bytes of the field at path of the n-th structure in the first file-block with the given code.
+// The path may name a field of an embedded structure, e.g. "id.name".
+// The returned slice shares its memory with the block data.
+func (f *File) FieldBytes(code Code, n int, path string) ([]byte, error) {
+	if _, err := f.SDNA(); err != nil {
+		return nil, err
+	}
+	blocks, ok := f.fileBlocks[code]
+	if !ok {
+		return nil, fmt.Errorf("blend: file block '%s' not found", code)
+	}
+	b := blocks[0]
+	if n < 0 || n >= int(b.Header.Count) {
+		return nil, fmt.Errorf("blend: structure %d out of range of block '%s' holding %d", n, code, b.Header.Count)
+	}
+	in, err := f.blockInstance(b, n)
+	if err != nil {
+		return nil, err
+	}
+	_, data, err := in.field(path)
+	return data, err
+}
+
+// decodeEntry is a decoded file-block along with the data it was decoded from.
+type decodeEntry struct {
+	data    []byte
+	decoded []map[string]interface{}
+}
+
+// decodeBlock decodes every structure stored in b, using the decode cache if enabled.
+// Blocks without an address are never cached as they can not be told apart.
+// A cached result is only used as long as the data of the block has not been replaced,
+// and callers get a copy of it, so they may modify the result, e.g. to pass it to EncodeBlock.
+func (f *File) decodeBlock(b *Block) ([]map[string]interface{}, error) {
+	if !f.isStructured(b) {
+		return []map[string]interface{}{{RawKey: b.Data}}, nil
+	}
+	addr := b.Header.OldMemoryAddress
+	if !f.cacheDecoded || addr == 0 {
+		return f.decodeStructs(b)
+	}
+	if e, ok := f.decoded[addr]; ok && sameBytes(e.data, b.Data) {
+		return copyDecoded(e.decoded), nil
+	}
+	decoded, err := f.decodeStructs(b)
+	if err != nil {
+		return nil, err
+	}
+	if f.decoded == nil {
+		f.decoded = make(map[uint64]decodeEntry)
+	}
+	f.decoded[addr] = decodeEntry{data: b.Data, decoded: decoded}
+	return copyDecoded(decoded), nil
+}
+
+// copyDecoded returns a deep copy of decoded structures, copying nested maps and slices.
+func copyDecoded(decoded []map[string]interface{}) []map[string]interface{} {
+	c := make([]map[string]interface{}, len(decoded))
+	for i, m := range decoded {
+		c[i] = copyValue(m).(map[string]interface{})
+	}
+	return c
+}
+
+// copyValue returns a deep copy of a decoded value. Values other than maps and slices are immutable.
+func copyValue(v interface{}) interface{} {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		m := make(map[string]interface{}, len(v))
+		for k, e := range v {
+			m[k] = copyValue(e)
+		}
+		return m
+	case []byte:
+		return append([]byte(nil), v...)
+	}
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Slice {
+		return v
+	}
+	c := reflect.MakeSlice(rv.Type(), rv.Len(), rv.Len())
+	for i := 0; i < rv.Len(); i++ {
+		e := copyValue(rv.Index(i).Interface())
+		c.Index(i).Set(reflect.ValueOf(e))
+	}
+	return c.Interface()
+}
+
+// sameBytes reports whether a and b are the same slice of the same backing array.
+func sameBytes(a, b []byte) bool {
+	return len(a) == len(b) && (len(a) == 0 || &a[0] == &b[0])
+}
+
+// resetDecodeCache drops all cached decoded blocks.
+func (f *File) resetDecodeCache() {
+	f.decoded = nil
+}
+
+// decodeStructs decodes every structure stored in b.
+func (f *File) decodeStructs(b *Block) ([]map[string]interface{}, error) {
+	if err := f.checkInstances(b, int(b.Header.Count)); err != nil {
+		return nil, err
+	}
+	decoded := make([]map[string]interface{}, 0, b.Header.Count)
+	for i := 0; i < int(b.Header.Count); i++ {
+		in, err := f.blockInstance(b, i)
+		if err != nil {
+			return nil, err
+		}
+		m, err := in.decode(0)
+		if err != nil {
+			return nil, fmt.Errorf("blend: unable to decode block '%s': %w", b.Header.Code, err)
+		}
+		decoded = append(decoded, m)
+	}
+	return decoded, nil
+}
+
+// decode decodes all fields of the instance, which is embedded in depth other structures.
+func (in *instance) decode(depth int) (map[string]interface{}, error) {
+	if depth > maxStructDepth {
+		return nil, fmt.Errorf("blend: structures nested deeper than %d levels in %s", maxStructDepth, in.typeName())
+	}
+	layout := in.sdna.layouts[in.idx]
+	m := make(map[string]interface{}, len(layout))
+	for _, l := range layout {
+		if !in.f.includePadding && isPadding(l.name) {
+			continue
+		}
+		b, err := safeSlice(in.data, l.offset, l.size)
+		if err != nil {
+			return nil, fmt.Errorf("blend: unable to read field '%s' of %s: %w", l.name, in.typeName(), err)
+		}
+		v, err := in.decodeField(l, b, depth)
+		if err != nil {
+			return nil, err
+		}
+		if values, ok := lookupEnum(in.typeName(), l.name); ok {
+			if raw, isInt := toInt64(v); isInt {
+				v = EnumValue{Value: raw, Name: values[int(raw)]}
+			}
+		}
+		m[l.name] = v
+	}
+	return m, nil
+}
+
+// decodeField decodes the bytes b of a single field of an instance embedded in depth other structures.
+func (in *instance) decodeField(l fieldLayout, b []byte, depth int) (interface{}, error) {
+	typeName := in.sdna.Types[l.typeIdx]
+	switch {
+	case l.pointerDepth > 0 && len(l.dims) == 0:
+		return in.f.readPointer(b, 0)
+	case len(l.dims) > 0 && typeName == "char" && l.pointerDepth == 0:
+		// the last dimension is the length of each string
+		dims := l.dims[:len(l.dims)-1]
+		return decodeArray(dims, b, reflect.TypeOf(""), func(e []byte) (interface{}, error) {
+			return byteSliceToString(e), nil
+		})
+	case len(l.dims) > 0 && l.pointerDepth > 0:
+		return decodeArray(l.dims, b, reflect.TypeOf(uint64(0)), func(e []byte) (interface{}, error) {
+			return in.f.readPointer(e, 0)
+		})
+	case len(l.dims) > 0:
+		// a corrupt SDNA may declare a basic type with a different size
+		size, ok := scalarSizes[typeName]
+		if !ok || int(in.sdna.Lengths[l.typeIdx]) != size {
+			return b, nil
+		}
+		elem, err := in.f.decodeScalar(typeName, make([]byte, size))
+		if err != nil {
+			return nil, err
+		}
+		return decodeArray(l.dims, b, reflect.TypeOf(elem), func(e []byte) (interface{}, error) {
+			return in.f.decodeScalar(typeName, e)
+		})
+	}
+
+	if idx, ok := in.sdna.structIndex(typeName); ok {
+		sub := &instance{
+			f:    in.f,
+			sdna: in.sdna,
+			idx:  idx,
+			data: b,
+		}
+		return sub.decode(depth + 1)
+	}
+	return in.f.decodeScalar(typeName, b)
+}
+
+// decodeArray decodes the array b with the given dimensions into nested slices of elements of type elem,
+// each element being decoded by decodeElem. Without dimensions, b is a single element.
+func decodeArray(dims []int, b []byte, elem reflect.Type, decodeElem func([]byte) (interface{}, error)) (interface{}, error) {
+	if len(dims) == 0 {
+		return decodeElem(b)
+	}
+	t := elem
+	for range dims[1:] {
+		t = reflect.SliceOf(t)
+	}
+	n := dims[0]
+	slice := reflect.MakeSlice(reflect.SliceOf(t), n, n)
+	if n == 0 {
+		return slice.Interface(), nil
+	}
+	size := len(b) / n
+	for i := 0; i < n; i++ {
+		v, err := decodeArray(dims[1:], b[i*size:(i+1)*size], elem, decodeElem)
+		if err != nil {
+			return nil, err
+		}
+		slice.Index(i).Set(reflect.ValueOf(v))
+	}
+	return slice.Interface(), nil
+}
+
+// isPadding reports whether name is the name of a field Blender only adds for alignment,
+// that is "pad" with an optional leading underscore and numeric suffix, e.g. "pad", "_pad" or "_pad_3".
+func isPadding(name string) bool {
+	name = strings.TrimPrefix(name, "_")
+	if !strings.HasPrefix(name, "pad") {
+		return false
+	}
+	suffix := strings.TrimPrefix(strings.TrimPrefix(name, "pad"), "_")
+	for _, c := range suffix {
+		if c < '0' || c > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// scalarSizes holds the size in bytes of the basic SDNA types.
+var scalarSizes = map[string]int{
+	"char": 1, "uchar": 1, "uint8_t": 1, "int8_t": 1,
+	"short": 2, "ushort": 2, "int16_t": 2, "uint16_t": 2,
+	"int": 4, "long": 4, "int32_t": 4, "uint": 4, "ulong": 4, "uint32_t": 4, "float": 4,
+	"int64_t": 8, "uint64_t": 8, "double": 8,
+}
+
+// decodeScalar decodes a value of a basic SDNA type into the matching Go type.
+// Types unknown to the decoder are kept as raw bytes.
+func (f *File) decodeScalar(typeName string, b []byte) (interface{}, error) {
+	if size, ok := scalarSizes[typeName]; !ok || len(b) < size {
+		return b, nil
+	}
+	switch typeName {
+	case "char", "uchar", "uint8_t":
+		return b[0], nil
+	case "int8_t":
+		return int8(b[0]), nil
+	case "short", "int16_t":
+		return int16(f.order.Uint16(b)), nil
+	case "ushort", "uint16_t":
+		return f.order.Uint16(b), nil
+	case "int", "long", "int32_t":
+		return int32(f.order.Uint32(b)), nil
+	case "uint", "ulong", "uint32_t":
+		return f.order.Uint32(b), nil
+	case "int64_t":
+		return int64(f.order.Uint64(b)), nil
+	case "uint64_t":
+		return f.order.Uint64(b), nil
+	case "float":
+		return math.Float32frombits(f.order.Uint32(b)), nil
+	case "double":
+		return math.Float64frombits(f.order.Uint64(b)), nil
+	}
+	return b, nil
+}
+
+// toInt64 widens a decoded integer value.
+func toInt64(v interface{}) (int64, bool) {
+	switch n := v.(type) {
+	case int:
+		return int64(n), true
+	case uint8:
+		return int64(n), true
+	case int8:
+		return int64(n), true
+	case int16:
+		return int64(n), true
+	case uint16:
+		return int64(n), true
+	case int32:
+		return int64(n), true
+	case uint32:
+		return int64(n), true
+	case int64:
+		return n, true
+	case uint64:
+		return int64(n), true
+	}
+	return 0, false
+}
@@ -0,0 +1,388 @@
+package blend
+
+import (
+	"bytes"
+	"errors"
+	"math"
+	"strings"
+	"testing"
+)
+
+func TestFile_DecodeBlock(t *testing.T) {
+	f := readExampleFile(t, "cubus-animated.blend")
+
+	decoded, err := f.DecodeBlock("OB")
+	if err != nil {
+		t.Fatalf("Expected nil error, got: %v", err)
+	}
+	if len(decoded) != 1 {
+		t.Fatalf("expected 1 decoded structure, got %d", len(decoded))
+	}
+	id, ok := decoded[0]["id"].(map[string]interface{})
+	if !ok {
+		t.Fatalf("expected id to decode to a map, got: %T", decoded[0]["id"])
+	}
+	if name := id["name"]; name != "OBCamera" {
+		t.Errorf("expected name 'OBCamera', got: %v", name)
+	}
+	if _, ok := decoded[0]["data"].(uint64); !ok {
+		t.Errorf("expected pointer data to decode to uint64, got: %T", decoded[0]["data"])
+	}
+}
+
+func TestFile_DecodeBlockNotFound(t *testing.T) {
+	f := readExampleFile(t, "cubus-animated.blend")
+
+	if _, err := f.DecodeBlock("XX"); err == nil {
+		t.Error("expected error decoding unknown block code")
+	}
+}
+
+func TestFile_IsStructured(t *testing.T) {
+	f := readExampleFile(t, "cubus-animated.blend")
+
+	testTable := map[Code]bool{
+		CodeObject: true,
+		CodeMesh:   true,
+		CodeData:   false,
+		CodeRender: false,
+		CodeTest:   false,
+		"XX":       false,
+	}
+	for code, expected := range testTable {
+		if structured := f.IsStructured(code); structured != expected {
+			t.Errorf("expected IsStructured('%s') to be %v, got %v", code, expected, structured)
+		}
+	}
+
+	// the RenderInfo and the thumbnail are written as raw data
+	for _, code := range []Code{CodeRender, CodeTest} {
+		decoded, err := f.DecodeBlock(code)
+		if err != nil {
+			t.Fatalf("Expected nil error, got: %v", err)
+		}
+		if len(decoded) != 1 || len(decoded[0]) != 1 {
+			t.Fatalf("expected block '%s' to decode to its raw data, got: %v", code, decoded)
+		}
+		if raw, ok := decoded[0][RawKey].([]byte); !ok || len(raw) != int(f.fileBlocks[code][0].Header.Size) {
+			t.Errorf("expected raw data of block '%s', got: %T", code, decoded[0][RawKey])
+		}
+	}
+}
+
+func TestFile_DecodeBlockRaw(t *testing.T) {
+	fx := newFixture(t)
+	fx.remove(CodeData)
+	fx.addRaw(CodeData, 0, 1, []byte{1, 2, 3, 4, 5})
+	f := fx.file()
+
+	decoded, err := f.DecodeBlock(CodeData)
+	if err != nil {
+		t.Fatalf("Expected nil error, got: %v", err)
+	}
+	if len(decoded) != 1 || len(decoded[0]) != 1 {
+		t.Fatalf("expected a single map holding the raw data, got: %v", decoded)
+	}
+	if raw, ok := decoded[0][RawKey].([]byte); !ok || !bytes.Equal(raw, []byte{1, 2, 3, 4, 5}) {
+		t.Errorf("expected raw data [1 2 3 4 5], got: %v", decoded[0][RawKey])
+	}
+
+	encoded, err := f.EncodeBlock(CodeData, decoded)
+	if err != nil {
+		t.Fatalf("Expected nil error, got: %v", err)
+	}
+	if !bytes.Equal(encoded, []byte{1, 2, 3, 4, 5}) {
+		t.Errorf("expected raw data to encode unchanged, got: %v", encoded)
+	}
+}
+
+func TestFile_FieldBytes(t *testing.T) {
+	f := readExampleFile(t, "cubus-animated.blend")
+
+	name, err := f.FieldBytes(CodeObject, 0, "id.name")
+	if err != nil {
+		t.Fatalf("Expected nil error, got: %v", err)
+	}
+	if len(name) != 66 || byteSliceToString(name) != "OBCamera" {
+		t.Errorf("expected 66 bytes holding 'OBCamera', got %d bytes: %q", len(name), name)
+	}
+	mat, err := f.FieldBytes(CodeObject, 0, "obmat")
+	if err != nil {
+		t.Fatalf("Expected nil error, got: %v", err)
+	}
+	if len(mat) != 64 {
+		t.Errorf("expected 64 bytes for float[4][4], got %d", len(mat))
+	}
+
+	for _, tt := range []struct {
+		code Code
+		n    int
+		path string
+	}{
+		{CodeObject, 1, "obmat"},
+		{CodeObject, -1, "obmat"},
+		{CodeObject, 0, "nosuchfield"},
+		{"XX", 0, "obmat"},
+	} {
+		if _, err := f.FieldBytes(tt.code, tt.n, tt.path); err == nil {
+			t.Errorf("expected error for field '%s' of structure %d in block '%s'", tt.path, tt.n, tt.code)
+		}
+	}
+}
+
+func TestFile_DecodeBlockCache(t *testing.T) {
+	f := readExampleFile(t, "cubus-animated.blend")
+
+	first, err := f.DecodeBlock("OB")
+	if err != nil {
+		t.Fatalf("Expected nil error, got: %v", err)
+	}
+	if len(f.decoded) != 1 {
+		t.Fatalf("expected the decoded block to be cached, got %d entries", len(f.decoded))
+	}
+	// modifying a result leaves the cache untouched
+	first[0]["id"].(map[string]interface{})["name"] = "OBRenamed"
+	first[0]["obmat"].([][]float32)[3][0] = 5
+	second, err := f.DecodeBlock("OB")
+	if err != nil {
+		t.Fatalf("Expected nil error, got: %v", err)
+	}
+	if name := second[0]["id"].(map[string]interface{})["name"]; name != "OBCamera" {
+		t.Errorf("expected cached name 'OBCamera', got: %v", name)
+	}
+	if x := second[0]["obmat"].([][]float32)[3][0]; x == 5 {
+		t.Error("expected cached matrix to be unchanged")
+	}
+
+	f.resetDecodeCache()
+	if len(f.decoded) != 0 {
+		t.Errorf("expected empty decode cache after reset, got %d entries", len(f.decoded))
+	}
+}
+
+func TestWithDecodeCache_disabled(t *testing.T) {
+	name := "cubus-animated.blend"
+	r, err := readExample(name)
+	if err != nil {
+		t.Fatalf("Unable to read example file '%s': %s", name, err)
+	}
+	defer r.Close()
+	f, err := NewFile(r, WithDecodeCache(false))
+	if err != nil {
+		t.Fatalf("Expected nil error, got: %v", err)
+	}
+
+	first, err := f.DecodeBlock("OB")
+	if err != nil {
+		t.Fatalf("Expected nil error, got: %v", err)
+	}
+	second, err := f.DecodeBlock("OB")
+	if err != nil {
+		t.Fatalf("Expected nil error, got: %v", err)
+	}
+	if &first[0] == &second[0] {
+		t.Error("expected decode without cache to return a new result")
+	}
+	if len(f.decoded) != 0 {
+		t.Errorf("expected empty decode cache, got %d entries", len(f.decoded))
+	}
+}
+
+func TestFile_DecodeBlockShortData(t *testing.T) {
+	fx := newFixture(t)
+	ob := fx.block("OB", 0)
+	ob.Data = ob.Data[:100]
+	ob.Header.Size = 100
+
+	_, err := fx.file().DecodeBlock("OB")
+	if !errors.Is(err, ErrShortBlockData) {
+		t.Fatalf("expected ErrShortBlockData, got: %v", err)
+	}
+	for _, s := range []string{"Object", "'OB'", "by 1316 bytes"} {
+		if !strings.Contains(err.Error(), s) {
+			t.Errorf("expected error to contain %q, got: %v", s, err)
+		}
+	}
+}
+
+func TestFile_DecodeBlockArrays(t *testing.T) {
+	fx := newFixture(t)
+	cube := fx.blockNamed(CodeObject, "OBCube")
+	fx.set(cube, 0, "loc", []float32{1, 2, 3})
+	// the matrix is modified below, which must not depend on the decode cache
+	f := fx.file(WithDecodeCache(false))
+	var decoded map[string]interface{}
+	for i, b := range f.fileBlocks[CodeObject] {
+		if b.Header.OldMemoryAddress == cube.Header.OldMemoryAddress {
+			m, err := f.decodeBlock(f.fileBlocks[CodeObject][i])
+			if err != nil {
+				t.Fatalf("Expected nil error, got: %v", err)
+			}
+			decoded = m[0]
+		}
+	}
+
+	loc, ok := decoded["loc"].([]float32)
+	if !ok || len(loc) != 3 || loc[0] != 1 || loc[2] != 3 {
+		t.Errorf("expected loc to decode to []float32{1, 2, 3}, got: %#v", decoded["loc"])
+	}
+	obmat, ok := decoded["obmat"].([][]float32)
+	if !ok || len(obmat) != 4 {
+		t.Fatalf("expected obmat to decode to 4 rows of []float32, got: %#v", decoded["obmat"])
+	}
+	for i, row := range obmat {
+		if len(row) != 4 {
+			t.Errorf("expected row %d of obmat to hold 4 floats, got: %v", i, row)
+		}
+	}
+	if obmat[3][3] != 1 {
+		t.Errorf("expected obmat to be a transform, got: %v", obmat)
+	}
+
+	ls, err := f.DecodeBlock(CodeLineStyle)
+	if err != nil {
+		t.Fatalf("Expected nil error, got: %v", err)
+	}
+	if mtex, ok := ls[0]["mtex"].([]uint64); !ok || len(mtex) != 18 {
+		t.Errorf("expected pointer array mtex to decode to 18 addresses, got: %#v", ls[0]["mtex"])
+	}
+
+	// modified arrays encode like the raw bytes they were decoded from
+	obmat[3][0] = 5
+	encoded, err := f.encodeBlock(cube, []map[string]interface{}{{"obmat": obmat, "loc": []float32{4, 5, 6}}})
+	if err != nil {
+		t.Fatalf("Expected nil error, got: %v", err)
+	}
+	cube = f.addresses[cube.Header.OldMemoryAddress]
+	cube.Data = encoded
+	in, err := f.blockInstance(cube, 0)
+	if err != nil {
+		t.Fatalf("Expected nil error, got: %v", err)
+	}
+	if x, err := in.float("loc"); err != nil || x != 4 {
+		t.Errorf("expected encoded loc to start with 4, got %v with error: %v", x, err)
+	}
+	_, raw, err := in.field("obmat")
+	if err != nil {
+		t.Fatalf("Expected nil error, got: %v", err)
+	}
+	if x := math.Float32frombits(f.order.Uint32(raw[48:])); x != 5 {
+		t.Errorf("expected encoded translation x of 5, got %v", x)
+	}
+	if _, err := f.encodeBlock(cube, []map[string]interface{}{{"loc": []float32{1, 2}}}); err == nil {
+		t.Error("expected error encoding an array of the wrong length")
+	}
+}
+
+func TestFile_DecodeBlockPadding(t *testing.T) {
+	f := readExampleFile(t, "cubus-animated.blend")
+
+	decoded, err := f.DecodeBlock(CodeObject)
+	if err != nil {
+		t.Fatalf("Expected nil error, got: %v", err)
+	}
+	var check func(m map[string]interface{})
+	check = func(m map[string]interface{}) {
+		for name, v := range m {
+			if strings.Contains(name, "pad") {
+				t.Errorf("expected no padding field, got '%s'", name)
+			}
+			if sub, ok := v.(map[string]interface{}); ok {
+				check(sub)
+			}
+		}
+	}
+	check(decoded[0])
+
+	name := "cubus-animated.blend"
+	r, err := readExample(name)
+	if err != nil {
+		t.Fatalf("Unable to read example file '%s': %s", name, err)
+	}
+	defer r.Close()
+	f, err = NewFile(r, WithIncludePadding())
+	if err != nil {
+		t.Fatalf("Expected nil error, got: %v", err)
+	}
+	decoded, err = f.DecodeBlock(CodeObject)
+	if err != nil {
+		t.Fatalf("Expected nil error, got: %v", err)
+	}
+	if _, ok := decoded[0]["id"].(map[string]interface{})["_pad"]; !ok {
+		t.Error("expected padding field '_pad' of ID with WithIncludePadding")
+	}
+}
+
+func TestIsPadding(t *testing.T) {
+	testTable := map[string]bool{
+		"pad":     true,
+		"pad1":    true,
+		"_pad":    true,
+		"_pad0":   true,
+		"_pad_3":  true,
+		"padding": false,
+		"spad":    false,
+		"pad_x":   false,
+		"type":    false,
+	}
+	for name, expected := range testTable {
+		if padding := isPadding(name); padding != expected {
+			t.Errorf("expected isPadding(%q) to be %v, got %v", name, expected, padding)
+		}
+	}
+}
+
+func TestFile_DecodeBlockSelfEmbedding(t *testing.T) {
+	fx := newFixture(t)
+	sdna := *fx.f.sdna
+	sdna.Structs = append([]DNAStruct{}, sdna.Structs...)
+	idx, _ := sdna.structIndex("Object")
+	st := &sdna.Structs[idx]
+	st.Fields = append([]DNAField{}, st.Fields...)
+	// let the first field of Object be an Object itself, as a corrupt file might
+	st.Fields[0].TypeIdx = st.TypeIdx
+	dna := fx.block(CodeDNA1, 0)
+	dna.Data = encodeSDNA(fx.f.order, &sdna)
+	dna.Header.Size = uint32(len(dna.Data))
+
+	_, err := fx.file().DecodeBlock(CodeObject)
+	if err == nil || !strings.Contains(err.Error(), "nested deeper") {
+		t.Errorf("expected error for a structure embedding itself, got: %v", err)
+	}
+}
+
+func TestFile_DecodeBlockNegativeDimensions(t *testing.T) {
+	fx := newFixture(t)
+	sdna := *fx.f.sdna
+	sdna.Names = append([]string{}, sdna.Names...)
+	for i, name := range sdna.Names {
+		if name == "obmat[4][4]" {
+			// the size of the field still adds up to 16 floats
+			sdna.Names[i] = "obmat[-4][-4]"
+		}
+	}
+	dna := fx.block(CodeDNA1, 0)
+	dna.Data = encodeSDNA(fx.f.order, &sdna)
+	dna.Header.Size = uint32(len(dna.Data))
+
+	if _, err := fx.file().DecodeBlock(CodeObject); !errors.Is(err, ErrMalformedSDNA) {
+		t.Errorf("expected ErrMalformedSDNA, got: %v", err)
+	}
+}
+
+func TestFile_DecodeBlockZeroLengthStruct(t *testing.T) {
+	fx := newFixture(t)
+	sdna := *fx.f.sdna
+	sdna.Lengths = append([]uint16{}, sdna.Lengths...)
+	idx, _ := sdna.structIndex("Object")
+	sdna.Lengths[sdna.Structs[idx].TypeIdx] = 0
+	dna := fx.block(CodeDNA1, 0)
+	dna.Data = encodeSDNA(fx.f.order, &sdna)
+	dna.Header.Size = uint32(len(dna.Data))
+	// without data, a huge count must not be taken for granted
+	fx.addRaw("XX", uint32(idx), 1<<30, nil)
+
+	if _, err := fx.file().DecodeBlock("XX"); !errors.Is(err, ErrStructSizeMismatch) {
+		t.Errorf("expected ErrStructSizeMismatch, got: %v", err)
+	}
+}
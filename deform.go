@@ -0,0 +1,116 @@
+package blend
+
+import "fmt"
+
+// VertexWeight is the weight of a vertex within a vertex group.
+type VertexWeight struct {
+	// Index of the vertex group, matching the order of VertexGroupNames
+	Group int
+	// Influence of the group on the vertex
+	Weight float32
+}
+
+// VertexGroups decodes the vertex group weights of the mesh located at meshAddr.
+// The returned map is keyed by vertex index and only contains vertices assigned to at least one group.
+func (f *File) VertexGroups(meshAddr uint64) (map[int][]VertexWeight, error) {
+	me, err := f.structAt(meshAddr, "Mesh")
+	if err != nil {
+		return nil, err
+	}
+	dvert, err := me.pointer("dvert")
+	if err != nil {
+		return nil, err
+	}
+	weights := make(map[int][]VertexWeight)
+	if dvert == 0 {
+		return weights, nil
+	}
+
+	b, err := f.blockByAddress(dvert)
+	if err != nil {
+		return nil, err
+	}
+	for i := 0; i < int(b.Header.Count); i++ {
+		dv, err := f.blockInstance(b, i)
+		if err != nil {
+			return nil, err
+		}
+		dw, err := dv.pointer("dw")
+		if err != nil {
+			return nil, err
+		}
+		total, err := dv.int("totweight")
+		if err != nil {
+			return nil, err
+		}
+		if dw == 0 || total <= 0 {
+			continue
+		}
+
+		wb, err := f.blockByAddress(dw)
+		if err != nil {
+			return nil, err
+		}
+		if total > int64(wb.Header.Count) {
+			return nil, fmt.Errorf("blend: vertex %d has %d weights, but only %d are stored", i, total, wb.Header.Count)
+		}
+		for j := 0; j < int(total); j++ {
+			w, err := f.blockInstance(wb, j)
+			if err != nil {
+				return nil, err
+			}
+			group, err := w.int("def_nr")
+			if err != nil {
+				return nil, err
+			}
+			weight, err := w.float("weight")
+			if err != nil {
+				return nil, err
+			}
+			weights[i] = append(weights[i], VertexWeight{
+				Group:  int(group),
+				Weight: float32(weight),
+			})
+		}
+	}
+	return weights, nil
+}
+
+// VertexGroupNames returns the names of the vertex groups of the object located at objectAddr, in index order.
+// Since Blender 3.0 the names are stored on the mesh, which is used if the object has no `defbase`.
+func (f *File) VertexGroupNames(objectAddr uint64) ([]string, error) {
+	ob, err := f.structAt(objectAddr, "Object")
+	if err != nil {
+		return nil, err
+	}
+	groups := ob
+	field := "defbase"
+	if !ob.hasField(field) {
+		data, err := ob.pointer("data")
+		if err != nil {
+			return nil, err
+		}
+		if groups, err = f.structAt(data, "Mesh"); err != nil {
+			return nil, err
+		}
+		field = "vertex_group_names"
+	}
+	first, err := groups.pointer(field + ".first")
+	if err != nil {
+		return nil, err
+	}
+
+	names := []string{}
+	err = f.walkList(first, func(g *instance) error {
+		name, err := g.string("name")
+		if err != nil {
+			return err
+		}
+		names = append(names, name)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return names, nil
+}
@@ -0,0 +1,74 @@
+package blend
+
+import "testing"
+
+func TestFile_VertexGroups(t *testing.T) {
+	fx := newFixture(t)
+	ob := fx.blockNamed("OB", "OBCube")
+	me := fx.blockNamed("ME", "MECube")
+
+	group := fx.add("DATA", "bDeformGroup", 1)
+	fx.set(group, 0, "name", "Bone")
+	fx.set(ob, 0, "defbase.first", group.Header.OldMemoryAddress)
+	fx.set(ob, 0, "defbase.last", group.Header.OldMemoryAddress)
+
+	weight := fx.add("DATA", "MDeformWeight", 1)
+	fx.set(weight, 0, "def_nr", 0)
+	fx.set(weight, 0, "weight", float32(0.5))
+	dverts := fx.add("DATA", "MDeformVert", 8)
+	fx.set(dverts, 3, "dw", weight.Header.OldMemoryAddress)
+	fx.set(dverts, 3, "totweight", 1)
+	fx.set(me, 0, "dvert", dverts.Header.OldMemoryAddress)
+
+	f := fx.file()
+	weights, err := f.VertexGroups(me.Header.OldMemoryAddress)
+	if err != nil {
+		t.Fatalf("Expected nil error, got: %v", err)
+	}
+	if len(weights) != 1 {
+		t.Fatalf("expected 1 weighted vertex, got: %v", weights)
+	}
+	w := weights[3]
+	if len(w) != 1 || w[0].Group != 0 || w[0].Weight != 0.5 {
+		t.Errorf("expected vertex 3 to have weight 0.5 in group 0, got: %v", w)
+	}
+
+	names, err := f.VertexGroupNames(ob.Header.OldMemoryAddress)
+	if err != nil {
+		t.Fatalf("Expected nil error, got: %v", err)
+	}
+	if len(names) != 1 || names[0] != "Bone" {
+		t.Errorf("expected group names [Bone], got: %v", names)
+	}
+}
+
+func TestFile_VertexGroupsNoDeformData(t *testing.T) {
+	f := readExampleFile(t, "cubus-animated.blend")
+	me := f.fileBlocks["ME"][0]
+
+	weights, err := f.VertexGroups(me.Header.OldMemoryAddress)
+	if err != nil {
+		t.Fatalf("Expected nil error, got: %v", err)
+	}
+	if len(weights) != 0 {
+		t.Errorf("expected no weights, got: %v", weights)
+	}
+
+	ob := f.fileBlocks["OB"][1]
+	names, err := f.VertexGroupNames(ob.Header.OldMemoryAddress)
+	if err != nil {
+		t.Fatalf("Expected nil error, got: %v", err)
+	}
+	if len(names) != 0 {
+		t.Errorf("expected no group names, got: %v", names)
+	}
+}
+
+func TestFile_VertexGroupsWrongType(t *testing.T) {
+	f := readExampleFile(t, "cubus-animated.blend")
+	ob := f.fileBlocks["OB"][0]
+
+	if _, err := f.VertexGroups(ob.Header.OldMemoryAddress); err == nil {
+		t.Error("expected error decoding vertex groups of an object")
+	}
+}
@@ -0,0 +1,30 @@
+package blend
+
+import (
+	"fmt"
+	"io"
+	"text/tabwriter"
+)
+
+// DumpBlocks writes a table of all file-blocks in the order of the file to w,
+// listing code, size, count, SDNA index, structure type and old memory address of each.
+// The type is left empty for blocks of raw data, or for all blocks if the SDNA can not be read.
+func (f *File) DumpBlocks(w io.Writer) error {
+	blocks, err := f.ReadAllBlocks()
+	if err != nil {
+		return err
+	}
+	sdna, _ := f.SDNA()
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "CODE\tSIZE\tCOUNT\tSDNA\tTYPE\tADDRESS")
+	for _, b := range blocks {
+		typeName := ""
+		if sdna != nil && f.isStructured(b) {
+			typeName = sdna.typeName(int(b.Header.SDNAIndex))
+		}
+		fmt.Fprintf(tw, "%s\t%d\t%d\t%d\t%s\t%#x\n",
+			b.Header.Code, b.Header.Size, b.Header.Count, b.Header.SDNAIndex, typeName, b.Header.OldMemoryAddress)
+	}
+	return tw.Flush()
+}
@@ -0,0 +1,36 @@
+package blend
+
+import (
+	"bytes"
+	"flag"
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+)
+
+var update = flag.Bool("update", false, "update golden files")
+
+func TestFile_DumpBlocks(t *testing.T) {
+	f := readExampleFile(t, "cubus-animated.blend")
+	if _, err := f.ReadAllBlocks(); err != nil {
+		t.Fatalf("Expected nil error, got: %v", err)
+	}
+
+	var buf bytes.Buffer
+	if err := f.DumpBlocks(&buf); err != nil {
+		t.Fatalf("Expected nil error, got: %v", err)
+	}
+	golden := filepath.Join("testdata", "dump-blocks.golden")
+	if *update {
+		if err := ioutil.WriteFile(golden, buf.Bytes(), 0644); err != nil {
+			t.Fatalf("Expected nil error, got: %v", err)
+		}
+	}
+	expected, err := ioutil.ReadFile(golden)
+	if err != nil {
+		t.Fatalf("Expected nil error, got: %v", err)
+	}
+	if !bytes.Equal(buf.Bytes(), expected) {
+		t.Errorf("expected output to equal %s, got:\n%s", golden, buf.Bytes())
+	}
+}
@@ -0,0 +1,219 @@
+package blend
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+)
+
+// EncodeBlock encodes instances into the on-disk layout of the first file-block with the given code,
+// accepting the values as returned by DecodeBlock. Arrays are given as nested slices like DecodeBlock returns them,
+// or as raw bytes of the size of the field. Encoding starts from the original bytes of the block,
+// so fields missing from a map and char arrays holding their decoded string keep their exact original bytes.
+// Encoding an unmodified decode therefore yields the original data.
+// The data of a block not holding structures is taken as is from the RawKey entry of a single instance.
+func (f *File) EncodeBlock(code Code, instances []map[string]interface{}) ([]byte, error) {
+	if _, err := f.SDNA(); err != nil {
+		return nil, err
+	}
+	blocks, ok := f.fileBlocks[code]
+	if !ok {
+		return nil, fmt.Errorf("file block '%s' not found", code)
+	}
+	return f.encodeBlock(blocks[0], instances)
+}
+
+// encodeBlock encodes instances into the layout of the structure referenced by b.
+func (f *File) encodeBlock(b *Block, instances []map[string]interface{}) ([]byte, error) {
+	if !f.isStructured(b) {
+		if len(instances) == 1 {
+			if raw, ok := instances[0][RawKey].([]byte); ok {
+				return append([]byte(nil), raw...), nil
+			}
+		}
+		return nil, fmt.Errorf("blend: block '%s' holds raw data, expected a single instance with '%s'", b.Header.Code, RawKey)
+	}
+	idx := int(b.Header.SDNAIndex)
+	if idx >= len(f.sdna.Structs) {
+		return nil, fmt.Errorf("blend: block '%s' references unknown sdna index %d", b.Header.Code, idx)
+	}
+	size := int(f.sdna.Lengths[f.sdna.Structs[idx].TypeIdx])
+	data := make([]byte, size*len(instances))
+	copy(data, b.Data)
+
+	for i, m := range instances {
+		in := &instance{
+			f:    f,
+			sdna: f.sdna,
+			idx:  idx,
+			data: data[i*size : (i+1)*size],
+		}
+		if err := in.encode(m); err != nil {
+			return nil, fmt.Errorf("blend: unable to encode block '%s': %w", b.Header.Code, err)
+		}
+	}
+	return data, nil
+}
+
+// encode writes the fields of m into the instance.
+func (in *instance) encode(m map[string]interface{}) error {
+	for name, v := range m {
+		l, ok := in.sdna.field(in.idx, name)
+		if !ok {
+			return fmt.Errorf("blend: %s has no field '%s'", in.typeName(), name)
+		}
+		b, err := safeSlice(in.data, l.offset, l.size)
+		if err != nil {
+			return fmt.Errorf("blend: unable to write field '%s' of %s: %w", name, in.typeName(), err)
+		}
+		if err := in.encodeField(l, b, v); err != nil {
+			return fmt.Errorf("blend: unable to write field '%s' of %s: %w", name, in.typeName(), err)
+		}
+	}
+	return nil
+}
+
+// encodeField writes the value v of a single field into b.
+func (in *instance) encodeField(l fieldLayout, b []byte, v interface{}) error {
+	typeName := in.sdna.Types[l.typeIdx]
+	switch val := v.(type) {
+	case map[string]interface{}:
+		idx, ok := in.sdna.structIndex(typeName)
+		if !ok || l.pointerDepth > 0 || len(l.dims) > 0 {
+			return fmt.Errorf("value of type %T for field of type %s", v, typeName)
+		}
+		sub := &instance{
+			f:    in.f,
+			sdna: in.sdna,
+			idx:  idx,
+			data: b,
+		}
+		return sub.encode(val)
+	case string:
+		if typeName != "char" || len(l.dims) != 1 || l.pointerDepth > 0 {
+			return fmt.Errorf("value of type %T for field of type %s", v, typeName)
+		}
+		return encodeString(b, val)
+	case []byte:
+		if len(val) != len(b) {
+			return fmt.Errorf("%d bytes for field of %d bytes", len(val), len(b))
+		}
+		copy(b, val)
+		return nil
+	case EnumValue:
+		v = val.Value
+	}
+
+	if len(l.dims) > 0 {
+		rv := reflect.ValueOf(v)
+		if rv.Kind() != reflect.Slice {
+			return fmt.Errorf("value of type %T for array", v)
+		}
+		dims := l.dims
+		if typeName == "char" && l.pointerDepth == 0 {
+			// the last dimension is the length of each string
+			dims = dims[:len(dims)-1]
+		}
+		return encodeArray(dims, b, rv, func(e []byte, v interface{}) error {
+			return in.encodeElement(l, typeName, e, v)
+		})
+	}
+	return in.encodeElement(l, typeName, b, v)
+}
+
+// encodeElement writes v as a single element of the field with layout l, which is an array element for arrays.
+func (in *instance) encodeElement(l fieldLayout, typeName string, b []byte, v interface{}) error {
+	if l.pointerDepth > 0 {
+		addr, ok := v.(uint64)
+		if !ok {
+			return fmt.Errorf("value of type %T for pointer", v)
+		}
+		if in.sdna.pointerSize == 4 {
+			in.f.order.PutUint32(b, uint32(addr))
+		} else {
+			in.f.order.PutUint64(b, addr)
+		}
+		return nil
+	}
+	if s, ok := v.(string); ok && typeName == "char" {
+		return encodeString(b, s)
+	}
+	return in.f.encodeScalar(typeName, b, v)
+}
+
+// encodeString writes s into the char array b, keeping the original bytes if b already holds s.
+func encodeString(b []byte, s string) error {
+	if byteSliceToString(b) == s {
+		return nil
+	}
+	if len(s) >= len(b) {
+		return fmt.Errorf("string of %d bytes exceeds char[%d]", len(s), len(b))
+	}
+	copy(b, s)
+	for i := len(s); i < len(b); i++ {
+		b[i] = 0
+	}
+	return nil
+}
+
+// encodeArray writes the nested slices v into the array b with the given dimensions,
+// each element being written by encodeElem.
+func encodeArray(dims []int, b []byte, v reflect.Value, encodeElem func([]byte, interface{}) error) error {
+	if len(dims) == 0 {
+		return encodeElem(b, v.Interface())
+	}
+	if v.Kind() != reflect.Slice || v.Len() != dims[0] {
+		return fmt.Errorf("value of type %v for array of %d elements", v.Type(), dims[0])
+	}
+	if dims[0] == 0 {
+		return nil
+	}
+	size := len(b) / dims[0]
+	for i := 0; i < dims[0]; i++ {
+		if err := encodeArray(dims[1:], b[i*size:(i+1)*size], v.Index(i), encodeElem); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// encodeScalar writes v as a value of a basic SDNA type.
+func (f *File) encodeScalar(typeName string, b []byte, v interface{}) error {
+	if size, ok := scalarSizes[typeName]; !ok || len(b) < size {
+		return fmt.Errorf("value of type %T for field of type %s", v, typeName)
+	}
+	switch typeName {
+	case "float", "double":
+		var x float64
+		switch n := v.(type) {
+		case float32:
+			x = float64(n)
+		case float64:
+			x = n
+		default:
+			return fmt.Errorf("value of type %T for field of type %s", v, typeName)
+		}
+		if typeName == "float" {
+			f.order.PutUint32(b, math.Float32bits(float32(x)))
+		} else {
+			f.order.PutUint64(b, math.Float64bits(x))
+		}
+		return nil
+	}
+
+	n, ok := toInt64(v)
+	if !ok {
+		return fmt.Errorf("value of type %T for field of type %s", v, typeName)
+	}
+	switch scalarSizes[typeName] {
+	case 1:
+		b[0] = byte(n)
+	case 2:
+		f.order.PutUint16(b, uint16(n))
+	case 4:
+		f.order.PutUint32(b, uint32(n))
+	case 8:
+		f.order.PutUint64(b, uint64(n))
+	}
+	return nil
+}
@@ -0,0 +1,81 @@
+package blend
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestFile_EncodeBlockRoundTrip(t *testing.T) {
+	f := readExampleFile(t, "cubus-animated.blend")
+	if _, err := f.SDNA(); err != nil {
+		t.Fatalf("Expected nil error, got: %v", err)
+	}
+
+	for _, b := range f.blocks {
+		// blocks referencing the first structure hold raw data
+		if b.Header.SDNAIndex == 0 {
+			continue
+		}
+		decoded, err := f.decodeBlock(b)
+		if err != nil {
+			t.Fatalf("Expected nil error decoding block '%s' at %#x, got: %v", b.Header.Code, b.Header.OldMemoryAddress, err)
+		}
+		encoded, err := f.encodeBlock(b, decoded)
+		if err != nil {
+			t.Fatalf("Expected nil error encoding block '%s' at %#x, got: %v", b.Header.Code, b.Header.OldMemoryAddress, err)
+		}
+		if !bytes.Equal(encoded, b.Data) {
+			t.Errorf("expected identical bytes for block '%s' at %#x", b.Header.Code, b.Header.OldMemoryAddress)
+		}
+	}
+}
+
+func TestFile_EncodeBlockModified(t *testing.T) {
+	f := readExampleFile(t, "cubus-animated.blend")
+
+	decoded, err := f.DecodeBlock("OB")
+	if err != nil {
+		t.Fatalf("Expected nil error, got: %v", err)
+	}
+	decoded[0]["id"].(map[string]interface{})["name"] = "OBCam"
+	decoded[0]["type"] = EnumValue{Value: 1}
+	decoded[0]["empty_drawsize"] = float32(2.5)
+
+	encoded, err := f.EncodeBlock("OB", decoded)
+	if err != nil {
+		t.Fatalf("Expected nil error, got: %v", err)
+	}
+	b := f.fileBlocks["OB"][0]
+	b.Data = encoded
+	reencoded, err := f.decodeBlock(b)
+	if err != nil {
+		t.Fatalf("Expected nil error, got: %v", err)
+	}
+
+	if name := reencoded[0]["id"].(map[string]interface{})["name"]; name != "OBCam" {
+		t.Errorf("expected name 'OBCam', got: %v", name)
+	}
+	if typ := reencoded[0]["type"].(EnumValue); typ.Name != "MESH" {
+		t.Errorf("expected type MESH, got: %+v", typ)
+	}
+	if size := reencoded[0]["empty_drawsize"]; size != float32(2.5) {
+		t.Errorf("expected empty_drawsize 2.5, got: %v", size)
+	}
+}
+
+func TestFile_EncodeBlockErrors(t *testing.T) {
+	f := readExampleFile(t, "cubus-animated.blend")
+
+	testTable := []map[string]interface{}{
+		{"unknown": 1},
+		{"type": "MESH"},
+		{"data": float32(1)},
+		{"loc": []byte{1}},
+		{"id": map[string]interface{}{"name": string(make([]byte, 66))}},
+	}
+	for _, m := range testTable {
+		if _, err := f.EncodeBlock("OB", []map[string]interface{}{m}); err == nil {
+			t.Errorf("expected error encoding %v", m)
+		}
+	}
+}
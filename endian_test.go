@@ -0,0 +1,134 @@
+package blend
+
+import (
+	"bytes"
+	"encoding/binary"
+	"reflect"
+	"testing"
+)
+
+func TestFile_bigEndianEndToEnd(t *testing.T) {
+	little := readExampleFile(t, "cubus-animated.blend")
+	sdna, err := little.SDNA()
+	if err != nil {
+		t.Fatalf("Expected nil error, got: %v", err)
+	}
+
+	// convert the example into a big endian file
+	h := little.header
+	buf := bytes.NewBuffer(nil)
+	buf.Write(h.Identifier[:])
+	buf.WriteByte(h.PointerSize)
+	buf.WriteByte('V')
+	buf.Write(h.Version[:])
+	for _, b := range little.blocks {
+		swapped := &Block{Header: b.Header, Data: append([]byte{}, b.Data...)}
+		switch {
+		case b.Header.Code == "DNA1":
+			swapped.Data = encodeSDNA(binary.BigEndian, sdna)
+		case b.Header.SDNAIndex != 0:
+			for i := 0; i < int(b.Header.Count); i++ {
+				size := int(sdna.Lengths[sdna.Structs[b.Header.SDNAIndex].TypeIdx])
+				swapStruct(sdna, int(b.Header.SDNAIndex), swapped.Data[i*size:(i+1)*size])
+			}
+		}
+		writeBlock(buf, binary.BigEndian, little.pointerSize, swapped)
+	}
+
+	big := parseFile(t, buf.Bytes())
+	if big.order != binary.BigEndian {
+		t.Fatalf("expected big endian byte order, got %v", big.order)
+	}
+	bigSDNA, err := big.readSDNA()
+	if err != nil {
+		t.Fatalf("Expected nil error, got: %v", err)
+	}
+	if !reflect.DeepEqual(bigSDNA.Names, sdna.Names) || !reflect.DeepEqual(bigSDNA.Types, sdna.Types) ||
+		!reflect.DeepEqual(bigSDNA.Lengths, sdna.Lengths) || !reflect.DeepEqual(bigSDNA.Structs, sdna.Structs) {
+		t.Error("expected identical sdna for big endian file")
+	}
+
+	if len(big.blocks) != len(little.blocks) {
+		t.Fatalf("expected %d blocks, got %d", len(little.blocks), len(big.blocks))
+	}
+	for i, b := range big.blocks {
+		l := little.blocks[i]
+		if b.Header != l.Header {
+			t.Errorf("expected header %+v at index %d, got %+v", l.Header, i, b.Header)
+			continue
+		}
+		if b.Header.SDNAIndex == 0 {
+			continue
+		}
+		expected, err := little.decodeBlock(l)
+		if err != nil {
+			t.Fatalf("Expected nil error, got: %v", err)
+		}
+		decoded, err := big.decodeBlock(b)
+		if err != nil {
+			t.Fatalf("Expected nil error, got: %v", err)
+		}
+		for _, m := range decoded {
+			swapRawArrays(bigSDNA, int(b.Header.SDNAIndex), m)
+		}
+		if !reflect.DeepEqual(decoded, expected) {
+			t.Errorf("expected identical decode of block '%s' at %#x", b.Header.Code, b.Header.OldMemoryAddress)
+		}
+	}
+}
+
+// swapStruct reverses the byte order of every field of the structure at index idx stored in data.
+func swapStruct(sdna *StructureDNA, idx int, data []byte) {
+	for _, l := range sdna.layouts[idx] {
+		field := data[l.offset : l.offset+l.size]
+		n := product(l.dims)
+		if l.pointerDepth > 0 {
+			swapElements(field, sdna.pointerSize)
+			continue
+		}
+		if sub, ok := sdna.structIndex(sdna.Types[l.typeIdx]); ok {
+			size := l.size / n
+			for i := 0; i < n; i++ {
+				swapStruct(sdna, sub, field[i*size:(i+1)*size])
+			}
+			continue
+		}
+		swapElements(field, int(sdna.Lengths[l.typeIdx]))
+	}
+}
+
+// swapRawArrays reverses the byte order of the arrays in a decoded structure, which are kept as raw bytes.
+func swapRawArrays(sdna *StructureDNA, idx int, m map[string]interface{}) {
+	for _, l := range sdna.layouts[idx] {
+		switch v := m[l.name].(type) {
+		case map[string]interface{}:
+			if sub, ok := sdna.structIndex(sdna.Types[l.typeIdx]); ok {
+				swapRawArrays(sdna, sub, v)
+			}
+		case []byte:
+			if l.pointerDepth > 0 {
+				swapElements(v, sdna.pointerSize)
+			} else if sub, ok := sdna.structIndex(sdna.Types[l.typeIdx]); ok {
+				size := len(v) / product(l.dims)
+				for i := 0; i+size <= len(v); i += size {
+					swapStruct(sdna, sub, v[i:i+size])
+				}
+			} else {
+				swapElements(v, int(sdna.Lengths[l.typeIdx]))
+			}
+		}
+	}
+}
+
+// swapElements reverses the bytes of each element of the given size in data.
+func swapElements(data []byte, size int) {
+	if size < 2 {
+		return
+	}
+	for i := 0; i+size <= len(data); i += size {
+		e := data[i : i+size]
+		for j, k := 0, len(e)-1; j < k; j, k = j+1, k-1 {
+			e[j], e[k] = e[k], e[j]
+		}
+	}
+}
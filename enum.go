@@ -0,0 +1,58 @@
+package blend
+
+import "sync"
+
+// EnumValue is a decoded enum field carrying its raw value and, if known, the name registered for it.
+type EnumValue struct {
+	Value int64
+	Name  string
+}
+
+var (
+	enumsMu sync.RWMutex
+	enums   = map[string]map[string]map[int]string{}
+)
+
+func init() {
+	lightTypes := map[int]string{
+		0: "POINT",
+		1: "SUN",
+		2: "SPOT",
+		3: "HEMI",
+		4: "AREA",
+	}
+	RegisterEnum("Object", "type", objectTypeNames)
+	// the Light struct was called Lamp up to Blender 2.80
+	RegisterEnum("Lamp", "type", lightTypes)
+	RegisterEnum("Light", "type", lightTypes)
+	RegisterEnum("ModifierData", "type", modifierTypeNames)
+	RegisterEnum("ParticleSettings", "type", particleTypeNames)
+	RegisterEnum("BezTriple", "ipo", interpolationNames)
+	RegisterEnum("bConstraint", "type", constraintTypeNames)
+	RegisterEnum("CustomDataLayer", "type", customDataTypeNames)
+}
+
+// RegisterEnum registers names for the values of the field fieldName of the SDNA struct typeName.
+// Decoded values of registered fields are returned as EnumValue.
+// Registering a field again replaces its previous names.
+func RegisterEnum(typeName, fieldName string, values map[int]string) {
+	names := make(map[int]string, len(values))
+	for k, v := range values {
+		names[k] = v
+	}
+
+	enumsMu.Lock()
+	defer enumsMu.Unlock()
+	if enums[typeName] == nil {
+		enums[typeName] = make(map[string]map[int]string)
+	}
+	enums[typeName][fieldName] = names
+}
+
+// lookupEnum returns the names registered for a field.
+func lookupEnum(typeName, fieldName string) (map[int]string, bool) {
+	enumsMu.RLock()
+	defer enumsMu.RUnlock()
+	values, ok := enums[typeName][fieldName]
+	return values, ok
+}
@@ -0,0 +1,47 @@
+package blend
+
+import "testing"
+
+func TestFile_DecodeBlockEnum(t *testing.T) {
+	f := readExampleFile(t, "cubus-animated.blend")
+
+	types := make(map[string]EnumValue)
+	for _, b := range f.fileBlocks["OB"] {
+		decoded, err := f.decodeBlock(b)
+		if err != nil {
+			t.Fatalf("Expected nil error, got: %v", err)
+		}
+		name := decoded[0]["id"].(map[string]interface{})["name"].(string)
+		v, ok := decoded[0]["type"].(EnumValue)
+		if !ok {
+			t.Fatalf("expected type of %s to decode to EnumValue, got: %T", name, decoded[0]["type"])
+		}
+		types[name] = v
+	}
+
+	expected := map[string]EnumValue{
+		"OBCube":   {Value: 1, Name: "MESH"},
+		"OBCamera": {Value: 11, Name: "CAMERA"},
+		"OBLight":  {Value: 10, Name: "LIGHT"},
+	}
+	for name, e := range expected {
+		if types[name] != e {
+			t.Errorf("expected type %+v for %s, got: %+v", e, name, types[name])
+		}
+	}
+}
+
+func TestRegisterEnum(t *testing.T) {
+	RegisterEnum("TestStruct", "mode", map[int]string{1: "ONE"})
+
+	values, ok := lookupEnum("TestStruct", "mode")
+	if !ok {
+		t.Fatal("expected registered enum to be found")
+	}
+	if values[1] != "ONE" {
+		t.Errorf("expected name 'ONE', got: %q", values[1])
+	}
+	if _, ok := lookupEnum("TestStruct", "other"); ok {
+		t.Error("expected unregistered field not to be found")
+	}
+}
@@ -0,0 +1,135 @@
+package blend
+
+import (
+	"errors"
+	"strings"
+)
+
+var (
+	// ErrInvalidIdentifier is returned if a file does not start with the BLENDER identifier.
+	ErrInvalidIdentifier = errors.New("blend: invalid identifier")
+	// ErrShortHeader is returned if a file ends within its 12 byte header.
+	ErrShortHeader = errors.New("blend: short header read")
+	// ErrUnsupportedCompression is returned by Open for compressed files it can not decompress.
+	ErrUnsupportedCompression = errors.New("blend: unsupported compression")
+	// ErrInvalidPointerSize is returned if the pointer size of the header is neither '-' nor '_'.
+	ErrInvalidPointerSize = errors.New("blend: invalid pointer size")
+	// ErrInvalidEndianness is returned if the endianness of the header is neither 'v' nor 'V'.
+	ErrInvalidEndianness = errors.New("blend: invalid endianness")
+	// ErrInvalidVersion is returned under strict validation if the version of the header is not made up of three digits.
+	ErrInvalidVersion = errors.New("blend: invalid version")
+	// ErrMissingEndBlock is returned if a file does not contain the terminating 'ENDB' block.
+	ErrMissingEndBlock = errors.New("blend: missing ENDB block")
+	// ErrMalformedEndBlock is returned under strict validation if the terminating 'ENDB' block declares data,
+	// which reveals that the sizes of earlier blocks were misread.
+	ErrMalformedEndBlock = errors.New("blend: malformed ENDB block")
+	// ErrInvalidSDNA is returned if a section of the SDNA does not start with its expected identifier.
+	ErrInvalidSDNA = errors.New("blend: invalid sdna")
+	// ErrMalformedSDNA is returned if the counts of the SDNA are implausibly large.
+	// This happens for corrupt files and if the byte order of a file was detected wrongly.
+	ErrMalformedSDNA = errors.New("blend: malformed sdna")
+	// ErrPointerSizeMismatch is returned under strict validation if the blocks of a file are laid out
+	// for a different pointer size than the one its header declares.
+	ErrPointerSizeMismatch = errors.New("blend: pointer size mismatch")
+	// ErrSizeMismatch is returned by Validate if a file is not of the size given by WithExpectedSize.
+	ErrSizeMismatch = errors.New("blend: size mismatch")
+	// ErrSDNAIndexOutOfRange is returned if a block references a structure the SDNA does not contain.
+	ErrSDNAIndexOutOfRange = errors.New("blend: sdna index out of range")
+	// ErrBlockDesync is returned under strict validation if a block does not start where the previous one ended.
+	ErrBlockDesync = errors.New("blend: block desync")
+	// ErrBuildInfoUnavailable is returned if a file does not record the build of Blender it was saved with.
+	ErrBuildInfoUnavailable = errors.New("blend: build info unavailable")
+	// ErrShortBlockData is returned if a block holds fewer bytes than needed for the data it declares.
+	ErrShortBlockData = errors.New("blend: short block data")
+	// ErrStructSizeMismatch is returned if a structure size does not match its fields or a block's size.
+	ErrStructSizeMismatch = errors.New("blend: struct size mismatch")
+	// ErrUnsupportedBlockCode is returned for files older than Blender 2.50 holding a block code
+	// which is not made up of characters followed by null or space padding.
+	ErrUnsupportedBlockCode = errors.New("blend: unsupported block code")
+	// ErrNoActiveObject is returned by ActiveObject if no object is active.
+	ErrNoActiveObject = errors.New("blend: no active object")
+	// ErrNoCamera is returned by ActiveCamera if a scene has no camera set.
+	ErrNoCamera = errors.New("blend: no camera")
+	// ErrNoCustomNormals is returned by MeshCustomNormals for meshes without custom normals.
+	ErrNoCustomNormals = errors.New("blend: no custom normals")
+	// ErrNoPreview is returned by Preview for datablocks without preview image.
+	ErrNoPreview = errors.New("blend: no preview")
+	// ErrDuplicateAddress is returned under strict validation if two blocks share the same memory address,
+	// which happens for corrupt files and if the size of an earlier block was misread.
+	ErrDuplicateAddress = errors.New("blend: duplicate address")
+	// ErrUnreadableType is returned when reading into a value whose size is not fixed, like a struct holding slices.
+	ErrUnreadableType = errors.New("blend: unreadable type")
+	// ErrReleased is returned when reading blocks after Release without calling Rewind.
+	ErrReleased = errors.New("blend: blocks released")
+)
+
+// ErrorKind classifies the errors returned by NewFile and Open.
+type ErrorKind int
+
+const (
+	// KindIO is the kind of errors reading from the underlying reader, which may succeed on retry.
+	KindIO ErrorKind = iota + 1
+	// KindFormat is the kind of errors for data which is no blend file or one this package can not read.
+	KindFormat
+	// KindValidation is the kind of errors for inconsistencies found by WithStrictValidation.
+	KindValidation
+)
+
+func (k ErrorKind) String() string {
+	switch k {
+	case KindIO:
+		return "io"
+	case KindFormat:
+		return "format"
+	case KindValidation:
+		return "validation"
+	}
+	return "unknown"
+}
+
+// OpenError is returned by NewFile and Open, telling apart failures to read from files which are invalid.
+// The underlying error is available through errors.Is and errors.As.
+type OpenError struct {
+	Kind ErrorKind
+	Err  error
+}
+
+func (e *OpenError) Error() string {
+	return e.Err.Error()
+}
+
+// Unwrap returns the underlying error.
+func (e *OpenError) Unwrap() error {
+	return e.Err
+}
+
+// MultiError collects several errors which occurred independently of each other.
+type MultiError []error
+
+func (m MultiError) Error() string {
+	msgs := make([]string, len(m))
+	for i, err := range m {
+		msgs[i] = err.Error()
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// Is reports whether any of the collected errors matches target.
+func (m MultiError) Is(target error) bool {
+	for _, err := range m {
+		if errors.Is(err, target) {
+			return true
+		}
+	}
+	return false
+}
+
+// As finds the first of the collected errors that matches target.
+func (m MultiError) As(target interface{}) bool {
+	for _, err := range m {
+		if errors.As(err, target) {
+			return true
+		}
+	}
+	return false
+}
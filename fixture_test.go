@@ -0,0 +1,287 @@
+package blend
+
+import (
+	"bytes"
+	"encoding/binary"
+	"math"
+	"testing"
+)
+
+// fixture builds modified copies of the example file for features the example does not cover.
+// Blocks are added and their fields set by name using the SDNA of the example.
+type fixture struct {
+	t        *testing.T
+	f        *File
+	blocks   []*Block
+	nextAddr uint64
+}
+
+// newFixture loads the example file as the base of a fixture.
+func newFixture(t *testing.T) *fixture {
+	t.Helper()
+	f := readExampleFile(t, "cubus-animated.blend")
+	if _, err := f.SDNA(); err != nil {
+		t.Fatalf("Expected nil error, got: %v", err)
+	}
+	return &fixture{
+		t:        t,
+		f:        f,
+		blocks:   f.blocks,
+		nextAddr: 0x10000000,
+	}
+}
+
+// block returns the n-th block with the given code.
+func (fx *fixture) block(code Code, n int) *Block {
+	fx.t.Helper()
+	blocks := fx.f.fileBlocks[code]
+	if n >= len(blocks) {
+		fx.t.Fatalf("fixture: no block '%s' #%d", code, n)
+	}
+	return blocks[n]
+}
+
+// blockNamed returns the block with the given code whose ID has the name, e.g. "OBCube".
+func (fx *fixture) blockNamed(code Code, name string) *Block {
+	fx.t.Helper()
+	for _, b := range fx.f.fileBlocks[code] {
+		in, err := fx.f.blockInstance(b, 0)
+		if err != nil {
+			fx.t.Fatalf("fixture: %v", err)
+		}
+		if n, _ := in.string("id.name"); n == name {
+			return b
+		}
+	}
+	fx.t.Fatalf("fixture: no block '%s' named '%s'", code, name)
+	return nil
+}
+
+// remove removes all blocks with the given code.
+func (fx *fixture) remove(code Code) {
+	var blocks []*Block
+	for _, b := range fx.blocks {
+		if b.Header.Code != code {
+			blocks = append(blocks, b)
+		}
+	}
+	fx.blocks = blocks
+}
+
+// add appends a zeroed block holding count structures of typeName and returns it.
+// It is inserted before the DNA1 block and located at a new unique address.
+func (fx *fixture) add(code Code, typeName string, count int) *Block {
+	fx.t.Helper()
+	idx, ok := fx.f.sdna.structIndex(typeName)
+	if !ok {
+		fx.t.Fatalf("fixture: unknown struct '%s'", typeName)
+	}
+	size := int(fx.f.sdna.Lengths[fx.f.sdna.Structs[idx].TypeIdx])
+	return fx.addRaw(code, uint32(idx), count, make([]byte, size*count))
+}
+
+// addRaw appends a block with the given SDNA index and data.
+func (fx *fixture) addRaw(code Code, sdnaIndex uint32, count int, data []byte) *Block {
+	fx.nextAddr += 0x1000
+	b := &Block{
+		Header: BlockHeader{
+			Code:             code,
+			Size:             uint32(len(data)),
+			OldMemoryAddress: fx.nextAddr,
+			SDNAIndex:        sdnaIndex,
+			Count:            uint32(count),
+		},
+		Data: data,
+	}
+	n := len(fx.blocks)
+	for i, e := range fx.blocks {
+		if e.Header.Code == "DNA1" {
+			n = i
+			break
+		}
+	}
+	blocks := append([]*Block{}, fx.blocks[:n]...)
+	blocks = append(blocks, b)
+	fx.blocks = append(blocks, fx.blocks[n:]...)
+	return b
+}
+
+// set writes value into the field at path of the n-th structure in b.
+// Supported values are integers, floats, []float32, strings for char arrays and uint64 for pointers.
+func (fx *fixture) set(b *Block, n int, path string, value interface{}) {
+	fx.t.Helper()
+	in, err := fx.f.blockInstance(b, n)
+	if err != nil {
+		fx.t.Fatalf("fixture: %v", err)
+	}
+	l, data, err := in.field(path)
+	if err != nil {
+		fx.t.Fatalf("fixture: %v", err)
+	}
+	order := fx.f.order
+	typeName := fx.f.sdna.Types[l.typeIdx]
+
+	switch v := value.(type) {
+	case string:
+		if len(v) >= len(data) {
+			fx.t.Fatalf("fixture: string '%s' too long for field '%s'", v, path)
+		}
+		for i := range data {
+			data[i] = 0
+		}
+		copy(data, v)
+	case []float32:
+		for i, x := range v {
+			order.PutUint32(data[4*i:], math.Float32bits(x))
+		}
+	case float32:
+		order.PutUint32(data, math.Float32bits(v))
+	case float64:
+		order.PutUint64(data, math.Float64bits(v))
+	case uint64:
+		if fx.f.pointerSize == 32 {
+			order.PutUint32(data, uint32(v))
+		} else {
+			order.PutUint64(data, v)
+		}
+	case int:
+		switch len(data) / maxInt(1, product(l.dims)) {
+		case 1:
+			data[0] = byte(v)
+		case 2:
+			order.PutUint16(data, uint16(v))
+		case 4:
+			order.PutUint32(data, uint32(v))
+		case 8:
+			order.PutUint64(data, uint64(v))
+		default:
+			fx.t.Fatalf("fixture: unsupported integer field '%s' of type %s", path, typeName)
+		}
+	default:
+		fx.t.Fatalf("fixture: unsupported value %T", value)
+	}
+}
+
+// renameType renames an SDNA type in the serialized fixture, e.g. to simulate files lacking a struct.
+// It has to be called after all fields are set, as the fixture keeps using the original SDNA.
+func (fx *fixture) renameType(name, newName string) {
+	fx.t.Helper()
+	sdna := *fx.f.sdna
+	sdna.Types = append([]string{}, sdna.Types...)
+	idx, ok := sdna.structIndex(name)
+	if !ok {
+		fx.t.Fatalf("fixture: unknown struct '%s'", name)
+	}
+	sdna.Types[sdna.Structs[idx].TypeIdx] = newName
+	dna := fx.block("DNA1", 0)
+	dna.Data = encodeSDNA(fx.f.order, &sdna)
+	dna.Header.Size = uint32(len(dna.Data))
+}
+
+// bytes serializes the fixture into the on-disk format.
+func (fx *fixture) bytes() []byte {
+	fx.t.Helper()
+	h := fx.f.header
+	buf := bytes.NewBuffer(nil)
+	buf.Write(h.Identifier[:])
+	buf.WriteByte(h.PointerSize)
+	buf.WriteByte(h.Endianness)
+	buf.Write(h.Version[:])
+	for _, b := range fx.blocks {
+		writeBlock(buf, fx.f.order, fx.f.pointerSize, b)
+	}
+	return buf.Bytes()
+}
+
+// file parses the serialized fixture with opts and reads all of its blocks.
+func (fx *fixture) file(opts ...Option) *File {
+	fx.t.Helper()
+	return parseFile(fx.t, fx.bytes(), opts...)
+}
+
+// parseFile parses data and reads all of its blocks.
+func parseFile(t testing.TB, data []byte, opts ...Option) *File {
+	t.Helper()
+	f, err := NewFile(bytes.NewReader(data), opts...)
+	if err != nil {
+		t.Fatalf("fixture: %v", err)
+	}
+	if err := f.loadBlocks(); err != nil {
+		t.Fatalf("fixture: %v", err)
+	}
+	return f
+}
+
+// encodeSDNA serializes s into the format of a DNA1 block.
+func encodeSDNA(order binary.ByteOrder, s *StructureDNA) []byte {
+	buf := bytes.NewBuffer(nil)
+	align := func() {
+		for buf.Len()%4 != 0 {
+			buf.WriteByte(0)
+		}
+	}
+	buf.WriteString("SDNANAME")
+	binary.Write(buf, order, uint32(len(s.Names)))
+	for _, n := range s.Names {
+		buf.WriteString(n)
+		buf.WriteByte(0)
+	}
+	align()
+	buf.WriteString("TYPE")
+	binary.Write(buf, order, uint32(len(s.Types)))
+	for _, n := range s.Types {
+		buf.WriteString(n)
+		buf.WriteByte(0)
+	}
+	align()
+	buf.WriteString("TLEN")
+	binary.Write(buf, order, s.Lengths)
+	align()
+	buf.WriteString("STRC")
+	binary.Write(buf, order, uint32(len(s.Structs)))
+	for _, st := range s.Structs {
+		binary.Write(buf, order, st.TypeIdx)
+		binary.Write(buf, order, uint16(len(st.Fields)))
+		binary.Write(buf, order, st.Fields)
+	}
+	return buf.Bytes()
+}
+
+// writeBlock writes the header and data of b.
+func writeBlock(buf *bytes.Buffer, order binary.ByteOrder, pointerSize uint8, b *Block) {
+	var code [4]byte
+	copy(code[:], b.Header.Code)
+	if pointerSize == 32 {
+		binary.Write(buf, order, FileBlockHeader32{
+			Code:             code,
+			Size:             b.Header.Size,
+			OldMemoryAddress: uint32(b.Header.OldMemoryAddress),
+			SDNAIndex:        b.Header.SDNAIndex,
+			Count:            b.Header.Count,
+		})
+	} else {
+		binary.Write(buf, order, FileBlockHeader64{
+			Code:             code,
+			Size:             b.Header.Size,
+			OldMemoryAddress: b.Header.OldMemoryAddress,
+			SDNAIndex:        b.Header.SDNAIndex,
+			Count:            b.Header.Count,
+		})
+	}
+	buf.Write(b.Data)
+}
+
+func product(dims []int) int {
+	p := 1
+	for _, d := range dims {
+		p *= d
+	}
+	return p
+}
+
+func maxInt(a, b int) int {
+	if a > b {
+		return a
+	}
+	return b
+}
@@ -12,16 +12,47 @@ type FileHeader struct {
 	Version [3]byte
 }
 
-// FileBlock64 represents a file-block if the file is encoded with 64 bits.
-type FileBlock64 struct {
-	header *FileBlockHeader64
-	data   []byte
+// Endianness is the byte order a file was written in.
+type Endianness int
+
+const (
+	// Little is the little endian byte order, marked by 'v' in the file header
+	Little Endianness = iota
+	// Big is the big endian byte order, marked by 'V' in the file header
+	Big
+)
+
+func (e Endianness) String() string {
+	switch e {
+	case Little:
+		return "little endian"
+	case Big:
+		return "big endian"
+	}
+	return "unknown endianness"
 }
 
-// FileBlock32 represents a file-block if the file is encoded with 32 bits.
-type FileBlock32 struct {
-	header *FileBlockHeader32
-	data   []byte
+// Block represents a file-block independent of the pointer size the file was encoded with.
+type Block struct {
+	Header BlockHeader
+	Data   []byte
+
+	// offset of the block header from the start of the file
+	offset int64
+}
+
+// BlockHeader is a file-block header with the memory address widened to 64 bits.
+type BlockHeader struct {
+	// File-block identifier
+	Code Code
+	// Total length of the data after the file-block header
+	Size uint32
+	// Memory address the structure was located when written to disk
+	OldMemoryAddress uint64
+	// Index of the SDNA structure
+	SDNAIndex uint32
+	// Number of structures located in this file-block
+	Count uint32
 }
 
 // FileBlockHeader64 represents a file-block header if the file is encoded with 64 bits.
@@ -66,12 +97,22 @@ type StructureDNA struct {
 	Lengths    []uint16
 	StructID   [4]byte
 	NumStructs uint32
-	Structs    []struct {
-		TypeIdx   uint16
-		NumFields uint16
-		Fields    []struct {
-			TypeIdx uint16
-			NameIdx uint16
-		}
-	}
+	Structs    []DNAStruct
+
+	pointerSize int
+	structIdx   map[string]int
+	layouts     [][]fieldLayout
+}
+
+// DNAStruct describes a single structure of the SDNA by referencing its type and fields.
+type DNAStruct struct {
+	TypeIdx   uint16
+	NumFields uint16
+	Fields    []DNAField
+}
+
+// DNAField references the type and name of a single structure field.
+type DNAField struct {
+	TypeIdx uint16
+	NameIdx uint16
 }
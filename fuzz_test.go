@@ -0,0 +1,45 @@
+//go:build go1.18
+// +build go1.18
+
+package blend
+
+import (
+	"bytes"
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+)
+
+func FuzzNewFile(f *testing.F) {
+	data, err := ioutil.ReadFile(filepath.Join("./examples", "cubus-animated.blend"))
+	if err != nil {
+		f.Fatalf("Expected nil error, got: %v", err)
+	}
+	f.Add(data)
+	f.Add(data[:12])
+	f.Add(header('-', 'V', "249"))
+
+	// a file holding only the SDNA of the example, so mutations reach the SDNA parser more often
+	example := parseFile(f, data)
+	buf := bytes.NewBuffer(data[:12:12])
+	writeBlock(buf, example.order, example.pointerSize, example.fileBlocks[CodeDNA1][0])
+	writeBlock(buf, example.order, example.pointerSize, &Block{Header: BlockHeader{Code: CodeEnd}})
+	f.Add(buf.Bytes())
+
+	f.Fuzz(func(t *testing.T, data []byte) {
+		file, err := NewFile(bytes.NewReader(data))
+		if err != nil {
+			return
+		}
+		if _, err := file.ReadAllBlocks(); err != nil {
+			return
+		}
+		if _, err := file.SDNA(); err != nil {
+			return
+		}
+		file.Validate()
+		for code := range file.fileBlocks {
+			file.DecodeBlock(code)
+		}
+	})
+}
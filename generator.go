@@ -0,0 +1,43 @@
+package blend
+
+import "errors"
+
+// LikelyGenerator makes a best guess at what wrote the file, returning "blender" for files that look like
+// Blender saved them and "unknown" otherwise. There is no flag telling the writer of a file, so the guess
+// relies on what Blender always writes, which tools generating files commonly leave out:
+//
+//   - the header holds a version of three digits
+//   - the file holds 'REND' and 'GLOB' blocks along with a window manager and at least one screen
+//   - 'DNA1' is the last block before 'ENDB'
+//   - files of Blender 2.76 and later record the hash of the build that saved them
+//
+// Data appended after 'ENDB' does not count against the file, as it is typically added by other tools later.
+func (f *File) LikelyGenerator() (string, error) {
+	blocks, err := f.ReadAllBlocks()
+	if err != nil {
+		return "", err
+	}
+	if !isDigits(f.header.Version[:]) {
+		return "unknown", nil
+	}
+	for _, code := range []Code{CodeRender, CodeGlobal, CodeWindowManager, CodeScreen} {
+		if f.BlockCount(code) == 0 {
+			return "unknown", nil
+		}
+	}
+	n := len(blocks)
+	if n < 2 || blocks[n-1].Header.Code != CodeEnd || blocks[n-2].Header.Code != CodeDNA1 {
+		return "unknown", nil
+	}
+	hash, _, err := f.BuildInfo()
+	if errors.Is(err, ErrBuildInfoUnavailable) {
+		return "blender", nil
+	}
+	if err != nil {
+		return "", err
+	}
+	if hash == "" {
+		return "unknown", nil
+	}
+	return "blender", nil
+}
@@ -0,0 +1,31 @@
+package blend
+
+import "testing"
+
+func TestFile_LikelyGenerator(t *testing.T) {
+	f := readExampleFile(t, "cubus-animated.blend")
+	if generator, err := f.LikelyGenerator(); err != nil || generator != "blender" {
+		t.Errorf("expected generator 'blender', got '%s' with error: %v", generator, err)
+	}
+
+	for name, modify := range map[string]func(fx *fixture){
+		"without window manager": func(fx *fixture) {
+			fx.remove(CodeWindowManager)
+		},
+		"without build hash": func(fx *fixture) {
+			fx.set(fx.block(CodeGlobal, 0), 0, "build_hash", "")
+		},
+		"with data after the sdna": func(fx *fixture) {
+			fx.addRaw(CodeData, 0, 1, []byte{1, 2, 3, 4})
+			n := len(fx.blocks)
+			// addRaw inserts blocks before 'DNA1', so the block is moved behind it
+			fx.blocks[n-3], fx.blocks[n-2] = fx.blocks[n-2], fx.blocks[n-3]
+		},
+	} {
+		fx := newFixture(t)
+		modify(fx)
+		if generator, err := fx.file().LikelyGenerator(); err != nil || generator != "unknown" {
+			t.Errorf("%s: expected generator 'unknown', got '%s' with error: %v", name, generator, err)
+		}
+	}
+}
@@ -0,0 +1,60 @@
+package blend
+
+import (
+	"fmt"
+	"time"
+)
+
+// global returns the FileGlobal structure stored in the 'GLOB' block.
+func (f *File) global() (*instance, error) {
+	if _, err := f.SDNA(); err != nil {
+		return nil, err
+	}
+	blocks, ok := f.fileBlocks[CodeGlobal]
+	if !ok {
+		return nil, fmt.Errorf("file block '%s' not found", CodeGlobal)
+	}
+	return f.blockInstance(blocks[0], 0)
+}
+
+// buildInfoVersion is the first version of Blender storing the build it was saved with, 2.76.
+const buildInfoVersion = 276
+
+// BuildInfo returns the commit hash and commit date of the Blender build that saved the file.
+// The date is formatted as "2006-01-02 15:04" in UTC, like Blender shows it.
+// Files saved by versions before 2.76 return ErrBuildInfoUnavailable.
+func (f *File) BuildInfo() (hash string, date string, err error) {
+	if major, minor := f.Version(); major*100+minor < buildInfoVersion {
+		return "", "", ErrBuildInfoUnavailable
+	}
+	g, err := f.global()
+	if err != nil {
+		return "", "", err
+	}
+	if !g.hasField("build_hash") || !g.hasField("build_commit_timestamp") {
+		return "", "", ErrBuildInfoUnavailable
+	}
+	hash, err = g.string("build_hash")
+	if err != nil {
+		return "", "", err
+	}
+	timestamp, err := g.int("build_commit_timestamp")
+	if err != nil {
+		return "", "", err
+	}
+	return hash, time.Unix(timestamp, 0).UTC().Format("2006-01-02 15:04"), nil
+}
+
+// SavedPath returns the absolute path the file was last saved to, or an empty string if none is recorded.
+func (f *File) SavedPath() (string, error) {
+	g, err := f.global()
+	if err != nil {
+		return "", err
+	}
+	// the field was renamed from filename to filepath in Blender 3.0
+	field := "filepath"
+	if !g.hasField(field) {
+		field = "filename"
+	}
+	return g.string(field)
+}
@@ -0,0 +1,57 @@
+package blend
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestFile_SavedPath(t *testing.T) {
+	f := readExampleFile(t, "cubus-animated.blend")
+
+	path, err := f.SavedPath()
+	if err != nil {
+		t.Fatalf("Expected nil error, got: %v", err)
+	}
+	expected := "/Users/michael/Desktop/cubus3-frame1.blend"
+	if path != expected {
+		t.Errorf("expected path '%s', got: '%s'", expected, path)
+	}
+}
+
+func TestFile_SavedPathBlank(t *testing.T) {
+	fx := newFixture(t)
+	fx.set(fx.block("GLOB", 0), 0, "filename", "")
+
+	path, err := fx.file().SavedPath()
+	if err != nil {
+		t.Fatalf("Expected nil error, got: %v", err)
+	}
+	if path != "" {
+		t.Errorf("expected empty path, got: '%s'", path)
+	}
+}
+
+func TestFile_BuildInfo(t *testing.T) {
+	f := readExampleFile(t, "cubus-animated.blend")
+
+	hash, date, err := f.BuildInfo()
+	if err != nil {
+		t.Fatalf("Expected nil error, got: %v", err)
+	}
+	if hash != "f6cb5f54494e" {
+		t.Errorf("expected hash 'f6cb5f54494e', got: '%s'", hash)
+	}
+	if date != "2019-07-29 14:47" {
+		t.Errorf("expected date '2019-07-29 14:47', got: '%s'", date)
+	}
+}
+
+func TestFile_BuildInfoUnavailable(t *testing.T) {
+	fx := newFixture(t)
+	copy(fx.f.header.Version[:], "275")
+
+	_, _, err := fx.file().BuildInfo()
+	if !errors.Is(err, ErrBuildInfoUnavailable) {
+		t.Errorf("expected ErrBuildInfoUnavailable, got: %v", err)
+	}
+}
@@ -0,0 +1,58 @@
+package blend
+
+import "fmt"
+
+// IDs calls yield with the code, name and old memory address of each ID datablock in the order of the file,
+// stopping early if yield returns false. ID datablocks are the blocks holding a structure which embeds an ID,
+// like objects, meshes and materials. IDs embedded in other datablocks, like the node tree of a material,
+// are stored in blocks of code 'DATA' and skipped. Names are returned without their two character type code.
+func (f *File) IDs(yield func(code Code, name string, addr uint64) bool) error {
+	if err := f.loadBlocks(); err != nil {
+		return err
+	}
+	sdna, err := f.SDNA()
+	if err != nil {
+		return err
+	}
+	for _, b := range f.blocks {
+		if b.Header.Code == CodeData || !f.isStructured(b) || !sdna.embedsID(int(b.Header.SDNAIndex)) {
+			continue
+		}
+		in, err := f.blockInstance(b, 0)
+		if err != nil {
+			return err
+		}
+		name, err := in.idName()
+		if err != nil {
+			return err
+		}
+		if !yield(b.Header.Code, name, b.Header.OldMemoryAddress) {
+			return nil
+		}
+	}
+	return nil
+}
+
+// UserCount returns the number of users of the ID datablock located at addr, which is the number of references
+// to it Blender counted when saving. Datablocks without users are orphans, which Blender drops when saving again
+// unless they have a fake user, which is counted as well.
+func (f *File) UserCount(addr uint64) (int, error) {
+	in, err := f.instanceAt(addr)
+	if err != nil {
+		return 0, err
+	}
+	if !in.sdna.embedsID(in.idx) {
+		return 0, fmt.Errorf("blend: %s at %#x is no ID datablock", in.typeName(), addr)
+	}
+	us, err := in.int("id.us")
+	if err != nil {
+		return 0, err
+	}
+	return int(us), nil
+}
+
+// embedsID reports whether the structure at index idx embeds an ID as its field `id`.
+func (s *StructureDNA) embedsID(idx int) bool {
+	l, ok := s.field(idx, "id")
+	return ok && s.Types[l.typeIdx] == "ID" && l.pointerDepth == 0 && len(l.dims) == 0
+}
@@ -0,0 +1,78 @@
+package blend
+
+import "testing"
+
+func TestFile_IDs(t *testing.T) {
+	f := readExampleFile(t, "cubus-animated.blend")
+
+	found := make(map[Code]map[string]bool)
+	err := f.IDs(func(code Code, name string, addr uint64) bool {
+		if b, err := f.blockByAddress(addr); err != nil || b.Header.Code != code {
+			t.Errorf("expected block '%s' at %#x, got: %v", code, addr, err)
+		}
+		if found[code] == nil {
+			found[code] = make(map[string]bool)
+		}
+		found[code][name] = true
+		return true
+	})
+	if err != nil {
+		t.Fatalf("Expected nil error, got: %v", err)
+	}
+	testTable := map[Code][]string{
+		CodeObject:   {"Camera", "Cube", "Light"},
+		CodeMesh:     {"Cube"},
+		CodeMaterial: {"Material"},
+		CodeCamera:   {"Camera"},
+		CodeLight:    {"Light"},
+	}
+	for code, expected := range testTable {
+		for _, name := range expected {
+			if !found[code][name] {
+				t.Errorf("expected '%s' among the '%s' datablocks, got: %v", name, code, found[code])
+			}
+		}
+	}
+	if _, ok := found[CodeData]; ok {
+		t.Errorf("expected no datablocks of code '%s'", CodeData)
+	}
+
+	n := 0
+	if err := f.IDs(func(Code, string, uint64) bool { n++; return false }); err != nil {
+		t.Fatalf("Expected nil error, got: %v", err)
+	}
+	if n != 1 {
+		t.Errorf("expected iteration to stop after the first datablock, got %d", n)
+	}
+}
+
+func TestFile_UserCount(t *testing.T) {
+	fx := newFixture(t)
+	mesh := fx.block(CodeMesh, 0)
+
+	// the mesh is used by the cube only
+	users, err := fx.f.UserCount(mesh.Header.OldMemoryAddress)
+	if err != nil {
+		t.Fatalf("Expected nil error, got: %v", err)
+	}
+	if users != 1 {
+		t.Errorf("expected 1 user of the mesh, got %d", users)
+	}
+
+	fx.set(mesh, 0, "id.us", 0)
+	if users, err := fx.file().UserCount(mesh.Header.OldMemoryAddress); err != nil || users != 0 {
+		t.Errorf("expected orphaned mesh, got %d users with error: %v", users, err)
+	}
+
+	in, err := fx.f.blockInstance(mesh, 0)
+	if err != nil {
+		t.Fatalf("Expected nil error, got: %v", err)
+	}
+	mvert, err := in.pointer("mvert")
+	if err != nil {
+		t.Fatalf("Expected nil error, got: %v", err)
+	}
+	if _, err := fx.f.UserCount(mvert); err == nil {
+		t.Error("expected error for an address not holding an ID datablock")
+	}
+}
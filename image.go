@@ -0,0 +1,69 @@
+package blend
+
+// Image is an image datablock, referencing an external file or holding packed data.
+type Image struct {
+	// Name of the datablock without its ID code
+	Name string
+	// Path of the image file, relative to the blend file if it starts with "//"
+	Filepath string
+	// Size of generated images; Blender does not store the size of images loaded from files
+	Width  int
+	Height int
+	// Whether the image data is packed into the blend file
+	Packed bool
+}
+
+// Images decodes all image datablocks of the file, whether packed or external.
+func (f *File) Images() ([]Image, error) {
+	var images []Image
+	err := f.eachStruct(CodeImage, func(im *instance) error {
+		name, err := im.idName()
+		if err != nil {
+			return err
+		}
+		path, err := imageFilepath(im)
+		if err != nil {
+			return err
+		}
+		width, err := im.int("gen_x")
+		if err != nil {
+			return err
+		}
+		height, err := im.int("gen_y")
+		if err != nil {
+			return err
+		}
+		packed, err := im.pointer("packedfile")
+		if err != nil {
+			return err
+		}
+		if packed == 0 && im.hasField("packedfiles") {
+			if packed, err = im.pointer("packedfiles.first"); err != nil {
+				return err
+			}
+		}
+
+		images = append(images, Image{
+			Name:     name,
+			Filepath: path,
+			Width:    int(width),
+			Height:   int(height),
+			Packed:   packed != 0,
+		})
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return images, nil
+}
+
+// imageFilepath returns the path of the image file of the Image im.
+func imageFilepath(im *instance) (string, error) {
+	// the path was stored in `name` before Blender 2.91
+	field := "filepath"
+	if !im.hasField(field) {
+		field = "name"
+	}
+	return im.string(field)
+}
@@ -0,0 +1,46 @@
+package blend
+
+import "testing"
+
+func TestFile_Images(t *testing.T) {
+	f := readExampleFile(t, "cubus-animated.blend")
+
+	images, err := f.Images()
+	if err != nil {
+		t.Fatalf("Expected nil error, got: %v", err)
+	}
+	if len(images) != 1 {
+		t.Fatalf("expected 1 image, got: %v", images)
+	}
+	if images[0].Name != "Render Result" {
+		t.Errorf("expected image 'Render Result', got: %q", images[0].Name)
+	}
+	if images[0].Packed {
+		t.Error("expected image not to be packed")
+	}
+}
+
+func TestFile_ImagesPacked(t *testing.T) {
+	fx := newFixture(t)
+	im := fx.block("IM", 0)
+	fx.set(im, 0, "name", "//textures/wood.png")
+	fx.set(im, 0, "gen_x", 512)
+	fx.set(im, 0, "gen_y", 256)
+	packed := fx.add("DATA", "PackedFile", 1)
+	fx.set(im, 0, "packedfile", packed.Header.OldMemoryAddress)
+
+	images, err := fx.file().Images()
+	if err != nil {
+		t.Fatalf("Expected nil error, got: %v", err)
+	}
+	expected := Image{
+		Name:     "Render Result",
+		Filepath: "//textures/wood.png",
+		Width:    512,
+		Height:   256,
+		Packed:   true,
+	}
+	if len(images) != 1 || images[0] != expected {
+		t.Errorf("expected %+v, got: %+v", expected, images)
+	}
+}
@@ -0,0 +1,398 @@
+package blend
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+)
+
+// maxListLength bounds the traversal of linked lists to protect against cycles in corrupt files.
+const maxListLength = 1 << 20
+
+// maxStructDepth bounds the nesting of embedded structures, which a corrupt SDNA may declare to contain themselves.
+const maxStructDepth = 64
+
+// instance is a view onto the bytes of a single structure stored within a file-block.
+type instance struct {
+	f    *File
+	sdna *StructureDNA
+	idx  int
+	data []byte
+}
+
+// instanceAt returns the first structure stored in the file-block located at addr.
+func (f *File) instanceAt(addr uint64) (*instance, error) {
+	if _, err := f.SDNA(); err != nil {
+		return nil, err
+	}
+	b, err := f.blockByAddress(addr)
+	if err != nil {
+		return nil, err
+	}
+	return f.blockInstance(b, 0)
+}
+
+// structAt returns the first structure stored at addr, which must be of type typeName.
+func (f *File) structAt(addr uint64, typeName string) (*instance, error) {
+	in, err := f.instanceAt(addr)
+	if err != nil {
+		return nil, err
+	}
+	if in.typeName() != typeName {
+		return nil, fmt.Errorf("blend: expected %s at address %#x, got %s", typeName, addr, in.typeName())
+	}
+	return in, nil
+}
+
+// blockInstance returns the n-th structure stored in the file-block b.
+func (f *File) blockInstance(b *Block, n int) (*instance, error) {
+	sdna, err := f.SDNA()
+	if err != nil {
+		return nil, err
+	}
+	idx := int(b.Header.SDNAIndex)
+	if idx >= len(sdna.Structs) {
+		return nil, fmt.Errorf("blend: block '%s' references unknown sdna index %d", b.Header.Code, idx)
+	}
+	size := int(sdna.Lengths[sdna.Structs[idx].TypeIdx])
+	data, err := safeSlice(b.Data, n*size, size)
+	if err != nil {
+		return nil, fmt.Errorf("blend: unable to read %s %d of block '%s' at %#x: %w",
+			sdna.typeName(idx), n, b.Header.Code, b.Header.OldMemoryAddress, err)
+	}
+	return &instance{
+		f:    f,
+		sdna: sdna,
+		idx:  idx,
+		data: data,
+	}, nil
+}
+
+// checkInstances verifies that b holds at least n structures of the type it references,
+// which allows allocating for n structures without trusting counts read from the file.
+func (f *File) checkInstances(b *Block, n int) error {
+	sdna, err := f.SDNA()
+	if err != nil {
+		return err
+	}
+	idx := int(b.Header.SDNAIndex)
+	if idx >= len(sdna.Structs) {
+		return fmt.Errorf("blend: block '%s' references unknown sdna index %d", b.Header.Code, idx)
+	}
+	size := int64(sdna.Lengths[sdna.Structs[idx].TypeIdx])
+	if size == 0 && n > 0 {
+		return fmt.Errorf("%w: block '%s' at %#x holds %s of length 0",
+			ErrStructSizeMismatch, b.Header.Code, b.Header.OldMemoryAddress, sdna.typeName(idx))
+	}
+	if n < 0 {
+		return fmt.Errorf("blend: negative number of structures %d in block '%s' at %#x", n, b.Header.Code, b.Header.OldMemoryAddress)
+	}
+	if size*int64(n) > int64(len(b.Data)) {
+		return fmt.Errorf("%w: %d bytes for %d %s of block '%s' at %#x exceed data length %d by %d bytes",
+			ErrShortBlockData, size*int64(n), n, sdna.typeName(idx), b.Header.Code, b.Header.OldMemoryAddress,
+			len(b.Data), size*int64(n)-int64(len(b.Data)))
+	}
+	return nil
+}
+
+// typeName returns the name of the structure type of the instance.
+func (in *instance) typeName() string {
+	return in.sdna.typeName(in.idx)
+}
+
+// field resolves a field by its path, e.g. "totvert" or "id.name", and returns its layout and bytes.
+// Each but the last element of the path must name an embedded structure.
+// Fields are also found by their aliases, see RegisterFieldAlias.
+func (in *instance) field(path string) (fieldLayout, []byte, error) {
+	idx, data := in.idx, in.data
+	parts := strings.Split(path, ".")
+	for i, part := range parts {
+		l, ok := in.sdna.fieldAny(idx, part)
+		if !ok {
+			return fieldLayout{}, nil, fmt.Errorf("blend: %s has no field '%s'", in.sdna.typeName(idx), part)
+		}
+		b, err := safeSlice(data, l.offset, l.size)
+		if err != nil {
+			return fieldLayout{}, nil, fmt.Errorf("blend: unable to read field '%s' of %s: %w", part, in.sdna.typeName(idx), err)
+		}
+		if i == len(parts)-1 {
+			return l, b, nil
+		}
+		sub, ok := in.sdna.structIndex(in.sdna.Types[l.typeIdx])
+		if !ok || l.pointerDepth > 0 || len(l.dims) > 0 {
+			return fieldLayout{}, nil, fmt.Errorf("blend: field '%s' of %s is not a structure", part, in.sdna.typeName(idx))
+		}
+		idx, data = sub, b
+	}
+	return fieldLayout{}, nil, errors.New("blend: empty field path")
+}
+
+// hasField reports whether the structure of the instance has a field with the given name or one of its aliases.
+func (in *instance) hasField(name string) bool {
+	_, ok := in.sdna.fieldAny(in.idx, name)
+	return ok
+}
+
+// sub returns the embedded structure at path as an instance of its own.
+func (in *instance) sub(path string) (*instance, error) {
+	l, b, err := in.field(path)
+	if err != nil {
+		return nil, err
+	}
+	idx, ok := in.sdna.structIndex(in.sdna.Types[l.typeIdx])
+	if !ok || l.pointerDepth > 0 || len(l.dims) > 0 {
+		return nil, fmt.Errorf("blend: field '%s' of %s is not a structure", path, in.typeName())
+	}
+	return &instance{
+		f:    in.f,
+		sdna: in.sdna,
+		idx:  idx,
+		data: b,
+	}, nil
+}
+
+// pointer reads the pointer field at path.
+func (in *instance) pointer(path string) (uint64, error) {
+	l, b, err := in.field(path)
+	if err != nil {
+		return 0, err
+	}
+	if l.pointerDepth == 0 {
+		return 0, fmt.Errorf("blend: field '%s' of %s is not a pointer", path, in.typeName())
+	}
+	return in.f.readPointer(b, 0)
+}
+
+// int reads the integer field at path, or its first element if it is an array.
+func (in *instance) int(path string) (int64, error) {
+	l, b, err := in.field(path)
+	if err != nil {
+		return 0, err
+	}
+	if l.pointerDepth > 0 {
+		return 0, fmt.Errorf("blend: field '%s' of %s is a pointer", path, in.typeName())
+	}
+	return in.f.decodeInt(in.sdna.Types[l.typeIdx], b)
+}
+
+// float reads the floating point field at path, or its first element if it is an array.
+func (in *instance) float(path string) (float64, error) {
+	l, b, err := in.field(path)
+	if err != nil {
+		return 0, err
+	}
+	if l.pointerDepth > 0 {
+		return 0, fmt.Errorf("blend: field '%s' of %s is a pointer", path, in.typeName())
+	}
+	return in.f.decodeFloat(in.sdna.Types[l.typeIdx], b)
+}
+
+// string reads the null-terminated char array at path.
+func (in *instance) string(path string) (string, error) {
+	l, b, err := in.field(path)
+	if err != nil {
+		return "", err
+	}
+	if l.pointerDepth > 0 || in.sdna.Types[l.typeIdx] != "char" {
+		return "", fmt.Errorf("blend: field '%s' of %s is not a char array", path, in.typeName())
+	}
+	return byteSliceToString(b), nil
+}
+
+// stringPointer reads the null-terminated string the char pointer at path points to, which is stored
+// in a raw data block of its own. A null pointer reads as an empty string.
+func (in *instance) stringPointer(path string) (string, error) {
+	l, _, err := in.field(path)
+	if err != nil {
+		return "", err
+	}
+	if l.pointerDepth != 1 || len(l.dims) > 0 || in.sdna.Types[l.typeIdx] != "char" {
+		return "", fmt.Errorf("blend: field '%s' of %s is not a char pointer", path, in.typeName())
+	}
+	addr, err := in.pointer(path)
+	if err != nil || addr == 0 {
+		return "", err
+	}
+	b, err := in.f.blockByAddress(addr)
+	if err != nil {
+		return "", err
+	}
+	return byteSliceToString(b.Data), nil
+}
+
+// element returns the i-th structure of the array of structures at path, e.g. the targets of a DriverVar.
+func (in *instance) element(path string, i int) (*instance, error) {
+	l, b, err := in.field(path)
+	if err != nil {
+		return nil, err
+	}
+	idx, ok := in.sdna.structIndex(in.sdna.Types[l.typeIdx])
+	if !ok || l.pointerDepth > 0 || len(l.dims) != 1 {
+		return nil, fmt.Errorf("blend: field '%s' of %s is not an array of structures", path, in.typeName())
+	}
+	if i < 0 || i >= l.dims[0] {
+		return nil, fmt.Errorf("blend: element %d out of range of field '%s' of %s holding %d", i, path, in.typeName(), l.dims[0])
+	}
+	size := l.size / l.dims[0]
+	return &instance{
+		f:    in.f,
+		sdna: in.sdna,
+		idx:  idx,
+		data: b[i*size : (i+1)*size],
+	}, nil
+}
+
+// idName returns the name of an ID datablock without its two character type code.
+func (in *instance) idName() (string, error) {
+	name, err := in.string("id.name")
+	if err != nil {
+		return "", err
+	}
+	if len(name) < 2 {
+		return "", nil
+	}
+	return name[2:], nil
+}
+
+// eachStruct calls fn with the first structure of every block with the given code, in disk order.
+func (f *File) eachStruct(code Code, fn func(*instance) error) error {
+	if _, err := f.SDNA(); err != nil {
+		return err
+	}
+	for _, b := range f.fileBlocks[code] {
+		in, err := f.blockInstance(b, 0)
+		if err != nil {
+			return err
+		}
+		if err := fn(in); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// walkList calls fn for each element of the linked list starting at the address first.
+// Elements are linked by a `next` pointer at the start of each structure,
+// which may be part of an embedded structure like the ModifierData of each modifier.
+func (f *File) walkList(first uint64, fn func(*instance) error) error {
+	seen := make(map[uint64]bool)
+	for addr := first; addr != 0; {
+		if seen[addr] || len(seen) >= maxListLength {
+			return fmt.Errorf("blend: cyclic list at address %#x", addr)
+		}
+		seen[addr] = true
+
+		in, err := f.instanceAt(addr)
+		if err != nil {
+			return err
+		}
+		if err := fn(in); err != nil {
+			return err
+		}
+		addr, err = in.next()
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// next reads the `next` pointer of a list element, descending into embedded structures at its start.
+func (in *instance) next() (uint64, error) {
+	prefix := ""
+	for idx, depth := in.idx, 0; depth < maxStructDepth; depth++ {
+		if _, ok := in.sdna.field(idx, "next"); ok {
+			return in.pointer(prefix + "next")
+		}
+		layout := in.sdna.layouts[idx]
+		if len(layout) == 0 {
+			break
+		}
+		l := layout[0]
+		sub, ok := in.sdna.structIndex(in.sdna.Types[l.typeIdx])
+		if !ok || l.pointerDepth > 0 || len(l.dims) > 0 {
+			break
+		}
+		prefix += l.name + "."
+		idx = sub
+	}
+	return 0, fmt.Errorf("blend: %s has no field 'next'", in.typeName())
+}
+
+// decodeInt decodes an integer of the given SDNA type.
+func (f *File) decodeInt(typeName string, b []byte) (int64, error) {
+	switch typeName {
+	case "char", "uchar", "uint8_t":
+		if len(b) >= 1 {
+			return int64(b[0]), nil
+		}
+	case "int8_t":
+		if len(b) >= 1 {
+			return int64(int8(b[0])), nil
+		}
+	case "short", "int16_t":
+		if len(b) >= 2 {
+			return int64(int16(f.order.Uint16(b))), nil
+		}
+	case "ushort", "uint16_t":
+		if len(b) >= 2 {
+			return int64(f.order.Uint16(b)), nil
+		}
+	case "int", "long", "int32_t":
+		if len(b) >= 4 {
+			return int64(int32(f.order.Uint32(b))), nil
+		}
+	case "uint", "ulong", "uint32_t":
+		if len(b) >= 4 {
+			return int64(f.order.Uint32(b)), nil
+		}
+	case "int64_t", "uint64_t":
+		if len(b) >= 8 {
+			return int64(f.order.Uint64(b)), nil
+		}
+	default:
+		return 0, fmt.Errorf("blend: type '%s' is not an integer", typeName)
+	}
+	return 0, fmt.Errorf("blend: not enough data for type '%s'", typeName)
+}
+
+// decodeFloat decodes a floating point number of the given SDNA type.
+func (f *File) decodeFloat(typeName string, b []byte) (float64, error) {
+	switch typeName {
+	case "float":
+		if len(b) >= 4 {
+			return float64(math.Float32frombits(f.order.Uint32(b))), nil
+		}
+	case "double":
+		if len(b) >= 8 {
+			return math.Float64frombits(f.order.Uint64(b)), nil
+		}
+	default:
+		return 0, fmt.Errorf("blend: type '%s' is not a floating point number", typeName)
+	}
+	return 0, fmt.Errorf("blend: not enough data for type '%s'", typeName)
+}
+
+// decodeVectors decodes consecutive float[3] vectors, as stored in raw data blocks.
+func (f *File) decodeVectors(raw []byte) [][3]float32 {
+	vectors := make([][3]float32, len(raw)/12)
+	for i := range vectors {
+		for j := 0; j < 3; j++ {
+			vectors[i][j] = math.Float32frombits(f.order.Uint32(raw[12*i+4*j:]))
+		}
+	}
+	return vectors
+}
+
+// safeSlice returns the n bytes of data starting at offset, or an error if they are out of range.
+func safeSlice(data []byte, offset, n int) ([]byte, error) {
+	if offset < 0 || n < 0 {
+		return nil, fmt.Errorf("blend: invalid range of %d bytes at offset %d", n, offset)
+	}
+	if offset > len(data) || n > len(data)-offset {
+		return nil, fmt.Errorf("%w: %d bytes at offset %d exceed data length %d by %d bytes",
+			ErrShortBlockData, n, offset, len(data), offset+n-len(data))
+	}
+	return data[offset : offset+n], nil
+}
@@ -0,0 +1,29 @@
+package blend
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+)
+
+// ResolveLibraryPath converts a path stored in the file, like the path of a linked library or an image,
+// into a path of the file system. Paths starting with "//" are relative to blendDir, the directory holding
+// the blend file, and may refer to its parents with "//../". Separators are normalized, as files saved
+// on Windows use backslashes. Absolute paths are returned unchanged.
+func (f *File) ResolveLibraryPath(libPath, blendDir string) (string, error) {
+	if strings.HasPrefix(libPath, "//") {
+		rel := strings.Replace(libPath[2:], `\`, "/", -1)
+		return filepath.Join(blendDir, filepath.FromSlash(rel)), nil
+	}
+	if filepath.IsAbs(libPath) || isWindowsAbs(libPath) {
+		return libPath, nil
+	}
+	return "", fmt.Errorf("blend: path '%s' is neither absolute nor relative to the blend file", libPath)
+}
+
+// isWindowsAbs reports whether path is an absolute Windows path like "C:\textures\wood.png",
+// which files saved on Windows hold regardless of the system reading them.
+func isWindowsAbs(path string) bool {
+	return len(path) >= 3 && path[1] == ':' && (path[2] == '\\' || path[2] == '/') &&
+		('a' <= path[0] && path[0] <= 'z' || 'A' <= path[0] && path[0] <= 'Z')
+}
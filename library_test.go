@@ -0,0 +1,34 @@
+package blend
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func TestFile_ResolveLibraryPath(t *testing.T) {
+	f := readExampleFile(t, "cubus-animated.blend")
+	dir := filepath.FromSlash("/projects/shot")
+
+	testTable := map[string]string{
+		"//textures/foo.png":    filepath.FromSlash("/projects/shot/textures/foo.png"),
+		`//textures\foo.png`:    filepath.FromSlash("/projects/shot/textures/foo.png"),
+		"//../assets/lib.blend": filepath.FromSlash("/projects/assets/lib.blend"),
+		"//./a//b/../c.blend":   filepath.FromSlash("/projects/shot/a/c.blend"),
+		"/assets/lib.blend":     "/assets/lib.blend",
+		`C:\assets\lib.blend`:   `C:\assets\lib.blend`,
+	}
+	for path, expected := range testTable {
+		resolved, err := f.ResolveLibraryPath(path, dir)
+		if err != nil {
+			t.Errorf("Expected nil error for '%s', got: %v", path, err)
+			continue
+		}
+		if resolved != expected {
+			t.Errorf("expected '%s' to resolve to '%s', got '%s'", path, expected, resolved)
+		}
+	}
+
+	if _, err := f.ResolveLibraryPath("textures/foo.png", dir); err == nil {
+		t.Error("expected error for a path relative to the working directory")
+	}
+}
@@ -0,0 +1,48 @@
+package blend
+
+// LineStyle is a Freestyle line style datablock stored in a file-block with code 'LS'.
+type LineStyle struct {
+	// Name of the datablock without its ID code
+	Name string
+	// Base color of the strokes
+	Color [3]float32
+	// Base transparency of the strokes
+	Alpha float32
+	// Base thickness of the strokes in pixels
+	Thickness float32
+}
+
+// LineStyles decodes all Freestyle line styles of the file.
+func (f *File) LineStyles() ([]LineStyle, error) {
+	var styles []LineStyle
+	err := f.eachStruct(CodeLineStyle, func(ls *instance) error {
+		name, err := ls.idName()
+		if err != nil {
+			return err
+		}
+		style := LineStyle{Name: name}
+		for i, field := range []string{"r", "g", "b"} {
+			c, err := ls.float(field)
+			if err != nil {
+				return err
+			}
+			style.Color[i] = float32(c)
+		}
+		alpha, err := ls.float("alpha")
+		if err != nil {
+			return err
+		}
+		thickness, err := ls.float("thickness")
+		if err != nil {
+			return err
+		}
+		style.Alpha = float32(alpha)
+		style.Thickness = float32(thickness)
+		styles = append(styles, style)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return styles, nil
+}
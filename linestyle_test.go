@@ -0,0 +1,37 @@
+package blend
+
+import "testing"
+
+func TestFile_LineStyles(t *testing.T) {
+	f := readExampleFile(t, "cubus-animated.blend")
+
+	styles, err := f.LineStyles()
+	if err != nil {
+		t.Fatalf("Expected nil error, got: %v", err)
+	}
+	expected := LineStyle{
+		Name:      "LineStyle",
+		Color:     [3]float32{0, 0, 0},
+		Alpha:     1,
+		Thickness: 3,
+	}
+	if len(styles) != 1 || styles[0] != expected {
+		t.Errorf("expected %+v, got: %+v", expected, styles)
+	}
+}
+
+func TestFile_LineStylesColor(t *testing.T) {
+	fx := newFixture(t)
+	ls := fx.block("LS", 0)
+	fx.set(ls, 0, "r", float32(0.25))
+	fx.set(ls, 0, "g", float32(0.5))
+	fx.set(ls, 0, "b", float32(0.75))
+
+	styles, err := fx.file().LineStyles()
+	if err != nil {
+		t.Fatalf("Expected nil error, got: %v", err)
+	}
+	if len(styles) != 1 || styles[0].Color != [3]float32{0.25, 0.5, 0.75} {
+		t.Errorf("expected color [0.25 0.5 0.75], got: %+v", styles)
+	}
+}
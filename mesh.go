@@ -0,0 +1,754 @@
+package blend
+
+import (
+	"errors"
+	"fmt"
+	"math"
+)
+
+// CustomData layer types of the layers decoded by the package.
+const (
+	cdNormal           = 8
+	cdPropInt32        = 11
+	cdMLoopUV          = 16
+	cdPropByteColor    = 17
+	cdCustomLoopNormal = 41
+	cdPropColor        = 47
+	cdPropFloat3       = 48
+)
+
+// Mesh is the geometry of a mesh in a form independent of Blender's data structures.
+type Mesh struct {
+	// Positions of the vertices
+	Vertices [][3]float32
+	// Vertex indices of each face in winding order
+	Faces [][]int
+	// Normals of the vertices, nil if the mesh stores none
+	Normals [][3]float32
+	// Coordinates of the active UV map per face corner as stored by the loops of the mesh, nil if it has no UV map
+	UVs [][2]float32
+}
+
+// ExtractMesh returns the geometry of the mesh used by the object located at objectAddr.
+func (f *File) ExtractMesh(objectAddr uint64) (*Mesh, error) {
+	b, err := f.ObjectData(objectAddr)
+	if err != nil {
+		return nil, err
+	}
+	if b == nil || b.Header.Code != CodeMesh {
+		return nil, fmt.Errorf("blend: object at %#x has no mesh", objectAddr)
+	}
+	addr := b.Header.OldMemoryAddress
+	me, err := f.structAt(addr, "Mesh")
+	if err != nil {
+		return nil, err
+	}
+
+	mesh := &Mesh{}
+	if mesh.Vertices, err = f.MeshVertices(addr); err != nil {
+		return nil, err
+	}
+	if mesh.Faces, err = f.MeshFaces(addr); err != nil {
+		return nil, err
+	}
+	hasNormals, err := f.hasMeshNormals(me)
+	if err != nil {
+		return nil, err
+	}
+	if hasNormals {
+		if mesh.Normals, err = f.MeshNormals(addr); err != nil {
+			return nil, err
+		}
+	}
+	hasUVs, err := f.hasMeshUVs(me)
+	if err != nil {
+		return nil, err
+	}
+	if hasUVs {
+		if mesh.UVs, err = f.MeshUVs(addr, ""); err != nil {
+			return nil, err
+		}
+	}
+	return mesh, nil
+}
+
+// MeshVertices returns the vertex positions of the mesh located at meshAddr.
+func (f *File) MeshVertices(meshAddr uint64) ([][3]float32, error) {
+	me, err := f.structAt(meshAddr, "Mesh")
+	if err != nil {
+		return nil, err
+	}
+	total, err := me.int("totvert")
+	if err != nil {
+		return nil, err
+	}
+	mvert, err := me.pointer("mvert")
+	if err != nil {
+		return nil, err
+	}
+	if mvert == 0 || total <= 0 {
+		return [][3]float32{}, nil
+	}
+	b, err := f.blockByAddress(mvert)
+	if err != nil {
+		return nil, err
+	}
+	if err := f.checkInstances(b, int(total)); err != nil {
+		return nil, err
+	}
+	vertices := make([][3]float32, total)
+	for i := range vertices {
+		v, err := f.blockInstance(b, i)
+		if err != nil {
+			return nil, err
+		}
+		_, raw, err := v.field("co")
+		if err != nil {
+			return nil, err
+		}
+		vectors := f.decodeVectors(raw)
+		if len(vectors) == 0 {
+			return nil, fmt.Errorf("blend: field 'co' of %s is not a vector", v.typeName())
+		}
+		vertices[i] = vectors[0]
+	}
+	return vertices, nil
+}
+
+// Edge is an edge of a mesh along with the attributes used for unwrapping and shading.
+type Edge struct {
+	// Indices of the vertices the edge connects
+	V1, V2 int
+	// Whether the edge is marked as seam for UV unwrapping
+	Seam bool
+	// Whether the edge is marked sharp for shading
+	Sharp bool
+	// Crease for subdivision surfaces from 0 to 1
+	Crease float32
+}
+
+// Flags of the MEdge `flag` field.
+const (
+	meSeam  = 1 << 2
+	meSharp = 1 << 9
+)
+
+// MeshEdges returns the edges of the mesh located at meshAddr.
+// Edges stored as generic attributes, as done since Blender 3.6, are not supported.
+func (f *File) MeshEdges(meshAddr uint64) ([]Edge, error) {
+	me, err := f.structAt(meshAddr, "Mesh")
+	if err != nil {
+		return nil, err
+	}
+	if _, ok := me.sdna.structIndex("MEdge"); !ok {
+		return nil, errors.New("blend: edges stored as generic attributes are not supported")
+	}
+	total, err := me.int("totedge")
+	if err != nil {
+		return nil, err
+	}
+	medge, err := me.pointer("medge")
+	if err != nil {
+		return nil, err
+	}
+	if medge == 0 || total <= 0 {
+		return []Edge{}, nil
+	}
+	b, err := f.blockByAddress(medge)
+	if err != nil {
+		return nil, err
+	}
+	if err := f.checkInstances(b, int(total)); err != nil {
+		return nil, err
+	}
+	edges := make([]Edge, total)
+	for i := range edges {
+		e, err := f.blockInstance(b, i)
+		if err != nil {
+			return nil, err
+		}
+		var values [4]int64
+		for j, field := range []string{"v1", "v2", "flag", "crease"} {
+			if values[j], err = e.int(field); err != nil {
+				return nil, err
+			}
+		}
+		edges[i] = Edge{
+			V1:     int(values[0]),
+			V2:     int(values[1]),
+			Seam:   values[2]&meSeam != 0,
+			Sharp:  values[2]&meSharp != 0,
+			Crease: float32(values[3]) / 255,
+		}
+	}
+	return edges, nil
+}
+
+// MeshFaces returns the vertex indices of each face of the mesh located at meshAddr.
+// Faces are read from the polygons and loops of the mesh, which files older than Blender 2.63 do not store.
+func (f *File) MeshFaces(meshAddr uint64) ([][]int, error) {
+	me, err := f.structAt(meshAddr, "Mesh")
+	if err != nil {
+		return nil, err
+	}
+	totpoly, err := me.int("totpoly")
+	if err != nil {
+		return nil, err
+	}
+	totloop, err := me.int("totloop")
+	if err != nil {
+		return nil, err
+	}
+	mpoly, err := me.pointer("mpoly")
+	if err != nil {
+		return nil, err
+	}
+	mloop, err := me.pointer("mloop")
+	if err != nil {
+		return nil, err
+	}
+	if mpoly == 0 || totpoly <= 0 {
+		return [][]int{}, nil
+	}
+	polys, err := f.blockByAddress(mpoly)
+	if err != nil {
+		return nil, err
+	}
+	loops, err := f.blockByAddress(mloop)
+	if err != nil {
+		return nil, err
+	}
+	if err := f.checkInstances(polys, int(totpoly)); err != nil {
+		return nil, err
+	}
+	if err := f.checkInstances(loops, int(totloop)); err != nil {
+		return nil, err
+	}
+
+	faces := make([][]int, totpoly)
+	for i := range faces {
+		p, err := f.blockInstance(polys, i)
+		if err != nil {
+			return nil, err
+		}
+		start, err := p.int("loopstart")
+		if err != nil {
+			return nil, err
+		}
+		n, err := p.int("totloop")
+		if err != nil {
+			return nil, err
+		}
+		if start < 0 || n < 0 || start+n > totloop {
+			return nil, fmt.Errorf("blend: loops %d to %d of face %d out of range of mesh at %#x with %d loops",
+				start, start+n, i, meshAddr, totloop)
+		}
+		faces[i] = make([]int, n)
+		for j := range faces[i] {
+			l, err := f.blockInstance(loops, int(start)+j)
+			if err != nil {
+				return nil, err
+			}
+			v, err := l.int("v")
+			if err != nil {
+				return nil, err
+			}
+			faces[i][j] = int(v)
+		}
+	}
+	return faces, nil
+}
+
+// MeshMaterialIndices returns the index into the materials of the mesh located at meshAddr for each of its faces.
+// Older files store the index in each MPoly, newer ones in the "material_index" attribute,
+// which is left out for meshes using a single material, so all faces use the first one.
+func (f *File) MeshMaterialIndices(meshAddr uint64) ([]int, error) {
+	me, err := f.structAt(meshAddr, "Mesh")
+	if err != nil {
+		return nil, err
+	}
+	totpoly, err := me.int("totpoly")
+	if err != nil {
+		return nil, err
+	}
+	if totpoly <= 0 {
+		return []int{}, nil
+	}
+	if ok, _ := me.sdna.HasField("MPoly", "mat_nr"); ok {
+		return f.mpolyMaterialIndices(me, int(totpoly))
+	}
+
+	layers, err := f.customDataLayers(me, "pdata", cdPropInt32)
+	if err != nil {
+		return nil, err
+	}
+	indices := make([]int, totpoly)
+	for _, l := range layers {
+		name, err := l.string("name")
+		if err != nil {
+			return nil, err
+		}
+		if name != "material_index" {
+			continue
+		}
+		data, err := l.pointer("data")
+		if err != nil {
+			return nil, err
+		}
+		b, err := f.blockByAddress(data)
+		if err != nil {
+			return nil, err
+		}
+		raw, err := safeSlice(b.Data, 0, 4*len(indices))
+		if err != nil {
+			return nil, fmt.Errorf("blend: unable to read material indices of block '%s' at %#x: %w", b.Header.Code, data, err)
+		}
+		for i := range indices {
+			indices[i] = int(int32(f.order.Uint32(raw[4*i:])))
+		}
+	}
+	return indices, nil
+}
+
+// mpolyMaterialIndices reads the material index stored in each of the first n MPoly of the mesh me.
+func (f *File) mpolyMaterialIndices(me *instance, n int) ([]int, error) {
+	mpoly, err := me.pointer("mpoly")
+	if err != nil {
+		return nil, err
+	}
+	if mpoly == 0 {
+		return []int{}, nil
+	}
+	polys, err := f.blockByAddress(mpoly)
+	if err != nil {
+		return nil, err
+	}
+	if err := f.checkInstances(polys, n); err != nil {
+		return nil, err
+	}
+	indices := make([]int, n)
+	for i := range indices {
+		p, err := f.blockInstance(polys, i)
+		if err != nil {
+			return nil, err
+		}
+		nr, err := p.int("mat_nr")
+		if err != nil {
+			return nil, err
+		}
+		indices[i] = int(nr)
+	}
+	return indices, nil
+}
+
+// MeshMaterials returns the names of the materials of the mesh located at meshAddr, indexed by MeshMaterialIndices.
+// Empty material slots are returned as empty names.
+func (f *File) MeshMaterials(meshAddr uint64) ([]string, error) {
+	me, err := f.structAt(meshAddr, "Mesh")
+	if err != nil {
+		return nil, err
+	}
+	totcol, err := me.int("totcol")
+	if err != nil {
+		return nil, err
+	}
+	mat, err := me.pointer("mat")
+	if err != nil {
+		return nil, err
+	}
+	if mat == 0 || totcol <= 0 {
+		return []string{}, nil
+	}
+	pointers, err := f.ResolvePointerArray(mat, int(totcol))
+	if err != nil {
+		return nil, err
+	}
+	names := make([]string, len(pointers))
+	for i, addr := range pointers {
+		if addr == 0 {
+			continue
+		}
+		ma, err := f.structAt(addr, "Material")
+		if err != nil {
+			return nil, err
+		}
+		if names[i], err = ma.idName(); err != nil {
+			return nil, err
+		}
+	}
+	return names, nil
+}
+
+// hasMeshNormals reports whether MeshNormals finds vertex normals for the mesh me.
+func (f *File) hasMeshNormals(me *instance) (bool, error) {
+	if ok, _ := me.sdna.HasField("MVert", "no"); ok {
+		return true, nil
+	}
+	layers, err := f.customDataLayers(me, "vdata", cdNormal)
+	return len(layers) > 0, err
+}
+
+// hasMeshUVs reports whether MeshUVs finds a UV map for the mesh me.
+func (f *File) hasMeshUVs(me *instance) (bool, error) {
+	if _, ok := me.sdna.structIndex("MLoopUV"); !ok {
+		return false, nil
+	}
+	layers, err := f.customDataLayers(me, "ldata", cdMLoopUV)
+	return len(layers) > 0, err
+}
+
+// MeshUVs returns the per-loop UV coordinates of the layer named layerName of the mesh located at meshAddr.
+// If layerName is empty, the active UV layer is used.
+// UV maps stored as generic attributes, as done since Blender 3.5, are not supported.
+func (f *File) MeshUVs(meshAddr uint64, layerName string) ([][2]float32, error) {
+	me, err := f.structAt(meshAddr, "Mesh")
+	if err != nil {
+		return nil, err
+	}
+	if _, ok := me.sdna.structIndex("MLoopUV"); !ok {
+		return nil, errors.New("blend: uv maps stored as generic attributes are not supported")
+	}
+
+	layers, err := f.customDataLayers(me, "ldata", cdMLoopUV)
+	if err != nil {
+		return nil, err
+	}
+	if len(layers) == 0 {
+		return nil, fmt.Errorf("blend: mesh at %#x has no uv maps", meshAddr)
+	}
+	layer := -1
+	if layerName == "" {
+		// every layer of a type stores the index of the active one among them
+		active, err := layers[0].int("active")
+		if err != nil {
+			return nil, err
+		}
+		if active < 0 || int(active) >= len(layers) {
+			return nil, fmt.Errorf("blend: active uv map %d of mesh at %#x out of range", active, meshAddr)
+		}
+		layer = int(active)
+	}
+	for i, l := range layers {
+		name, err := l.string("name")
+		if err != nil {
+			return nil, err
+		}
+		if layerName != "" && name == layerName {
+			layer = i
+		}
+	}
+	if layer == -1 {
+		return nil, fmt.Errorf("blend: mesh at %#x has no uv map '%s'", meshAddr, layerName)
+	}
+
+	data, err := layers[layer].pointer("data")
+	if err != nil {
+		return nil, err
+	}
+	b, err := f.blockByAddress(data)
+	if err != nil {
+		return nil, err
+	}
+	if err := f.checkInstances(b, int(b.Header.Count)); err != nil {
+		return nil, err
+	}
+	uvs := make([][2]float32, b.Header.Count)
+	for i := range uvs {
+		uv, err := f.blockInstance(b, i)
+		if err != nil {
+			return nil, err
+		}
+		_, raw, err := uv.field("uv")
+		if err != nil {
+			return nil, err
+		}
+		for j := range uvs[i] {
+			v, err := f.decodeFloat("float", raw[4*j:])
+			if err != nil {
+				return nil, err
+			}
+			uvs[i][j] = float32(v)
+		}
+	}
+	return uvs, nil
+}
+
+// MeshVertexColors returns the per-loop colors of the color layer named layerName of the mesh located at meshAddr.
+// If layerName is empty, the active color layer is used, preferring byte colors over float colors.
+// Byte colors, stored in MLoopCol by older files, are returned as is. Float colors are stored in linear space
+// and converted to sRGB bytes like Blender does for byte colors. Colors stored per vertex are not supported.
+func (f *File) MeshVertexColors(meshAddr uint64, layerName string) ([][4]uint8, error) {
+	me, err := f.structAt(meshAddr, "Mesh")
+	if err != nil {
+		return nil, err
+	}
+	var layers []*instance
+	for _, t := range []int{cdPropByteColor, cdPropColor} {
+		l, err := f.customDataLayers(me, "ldata", t)
+		if err != nil {
+			return nil, err
+		}
+		if layerName == "" && len(l) > 0 {
+			// every layer of a type stores the index of the active one among them
+			active, err := l[0].int("active")
+			if err != nil {
+				return nil, err
+			}
+			if active < 0 || int(active) >= len(l) {
+				return nil, fmt.Errorf("blend: active color layer %d of mesh at %#x out of range", active, meshAddr)
+			}
+			layers = l[active : active+1]
+			break
+		}
+		layers = append(layers, l...)
+	}
+	if len(layers) == 0 {
+		return nil, fmt.Errorf("blend: mesh at %#x has no vertex colors", meshAddr)
+	}
+	layer := layers[0]
+	if layerName != "" {
+		layer = nil
+		for _, l := range layers {
+			name, err := l.string("name")
+			if err != nil {
+				return nil, err
+			}
+			if name == layerName {
+				layer = l
+				break
+			}
+		}
+		if layer == nil {
+			return nil, fmt.Errorf("blend: mesh at %#x has no color layer '%s'", meshAddr, layerName)
+		}
+	}
+
+	totloop, err := me.int("totloop")
+	if err != nil {
+		return nil, err
+	}
+	t, err := layer.int("type")
+	if err != nil {
+		return nil, err
+	}
+	data, err := layer.pointer("data")
+	if err != nil {
+		return nil, err
+	}
+	b, err := f.blockByAddress(data)
+	if err != nil {
+		return nil, err
+	}
+	size := 4
+	if t == cdPropColor {
+		size = 16
+	}
+	raw, err := safeSlice(b.Data, 0, int(totloop)*size)
+	if err != nil {
+		return nil, fmt.Errorf("blend: unable to read colors of block '%s' at %#x: %w", b.Header.Code, data, err)
+	}
+	colors := make([][4]uint8, totloop)
+	for i := range colors {
+		for j := range colors[i] {
+			if t == cdPropByteColor {
+				colors[i][j] = raw[4*i+j]
+				continue
+			}
+			v := math.Float32frombits(f.order.Uint32(raw[16*i+4*j:]))
+			// alpha is not affected by the color space
+			if j < 3 {
+				v = linearToSRGB(v)
+			}
+			colors[i][j] = uint8(math.Round(float64(clamp01(v)) * 255))
+		}
+	}
+	return colors, nil
+}
+
+// linearToSRGB converts a linear color component to the sRGB color space.
+func linearToSRGB(v float32) float32 {
+	if v <= 0.0031308 {
+		return v * 12.92
+	}
+	return float32(1.055*math.Pow(float64(v), 1/2.4) - 0.055)
+}
+
+// clamp01 limits v to the range of 0 to 1, mapping NaN to 0.
+func clamp01(v float32) float32 {
+	if !(v > 0) {
+		return 0
+	}
+	if v > 1 {
+		return 1
+	}
+	return v
+}
+
+// MeshNormals returns the normalized vertex normals of the mesh located at meshAddr.
+// Older files store them as shorts in each MVert, newer ones in a float normal layer, if at all.
+func (f *File) MeshNormals(meshAddr uint64) ([][3]float32, error) {
+	me, err := f.structAt(meshAddr, "Mesh")
+	if err != nil {
+		return nil, err
+	}
+	if ok, _ := me.sdna.HasField("MVert", "no"); ok {
+		return f.mvertNormals(me)
+	}
+
+	layers, err := f.customDataLayers(me, "vdata", cdNormal)
+	if err != nil {
+		return nil, err
+	}
+	if len(layers) == 0 {
+		return nil, fmt.Errorf("blend: mesh at %#x stores no vertex normals", meshAddr)
+	}
+	total, err := me.int("totvert")
+	if err != nil {
+		return nil, err
+	}
+	data, err := layers[0].pointer("data")
+	if err != nil {
+		return nil, err
+	}
+	b, err := f.blockByAddress(data)
+	if err != nil {
+		return nil, err
+	}
+	raw, err := safeSlice(b.Data, 0, int(total)*12)
+	if err != nil {
+		return nil, fmt.Errorf("blend: unable to read normals of block '%s' at %#x: %w", b.Header.Code, data, err)
+	}
+	return f.decodeVectors(raw), nil
+}
+
+// MeshCustomNormals returns the custom split normals of the mesh located at meshAddr, one per loop.
+// They are read from the "custom_normal" float vector attribute of the face corners, as stored since Blender 4.5.
+// Older files store custom normals relative to the loop normal spaces of the mesh, which depend on its smoothing
+// and are not recomputed here, so decoding them fails; see MeshCustomLoopNormals for their stored values.
+// ErrNoCustomNormals is returned if the mesh has no custom normals.
+func (f *File) MeshCustomNormals(meshAddr uint64) ([][3]float32, error) {
+	me, err := f.structAt(meshAddr, "Mesh")
+	if err != nil {
+		return nil, err
+	}
+	layers, err := f.customDataLayers(me, "ldata", cdPropFloat3)
+	if err != nil {
+		return nil, err
+	}
+	var layer *instance
+	for _, l := range layers {
+		name, err := l.string("name")
+		if err != nil {
+			return nil, err
+		}
+		if name == "custom_normal" {
+			layer = l
+			break
+		}
+	}
+	if layer == nil {
+		legacy, err := f.customDataLayers(me, "ldata", cdCustomLoopNormal)
+		if err != nil {
+			return nil, err
+		}
+		if len(legacy) > 0 {
+			return nil, fmt.Errorf("blend: custom normals of mesh at %#x are relative to loop normal spaces, see MeshCustomLoopNormals", meshAddr)
+		}
+		return nil, ErrNoCustomNormals
+	}
+
+	totloop, err := me.int("totloop")
+	if err != nil {
+		return nil, err
+	}
+	data, err := layer.pointer("data")
+	if err != nil {
+		return nil, err
+	}
+	b, err := f.blockByAddress(data)
+	if err != nil {
+		return nil, err
+	}
+	raw, err := safeSlice(b.Data, 0, int(totloop)*12)
+	if err != nil {
+		return nil, fmt.Errorf("blend: unable to read custom normals of block '%s' at %#x: %w", b.Header.Code, data, err)
+	}
+	return f.decodeVectors(raw), nil
+}
+
+// MeshCustomLoopNormals returns the custom split normals of the mesh located at meshAddr as stored in its
+// CUSTOMLOOPNORMAL layer before Blender 4.5, one per loop. Each normal is a pair of shorts scaled to the range
+// of -32767 to 32767, which are the angle factor and the reference factor of the normal within the loop
+// normal space of the loop. ErrNoCustomNormals is returned if the mesh has no such layer.
+func (f *File) MeshCustomLoopNormals(meshAddr uint64) ([][2]int16, error) {
+	me, err := f.structAt(meshAddr, "Mesh")
+	if err != nil {
+		return nil, err
+	}
+	layers, err := f.customDataLayers(me, "ldata", cdCustomLoopNormal)
+	if err != nil {
+		return nil, err
+	}
+	if len(layers) == 0 {
+		return nil, ErrNoCustomNormals
+	}
+	totloop, err := me.int("totloop")
+	if err != nil {
+		return nil, err
+	}
+	data, err := layers[0].pointer("data")
+	if err != nil {
+		return nil, err
+	}
+	b, err := f.blockByAddress(data)
+	if err != nil {
+		return nil, err
+	}
+	raw, err := safeSlice(b.Data, 0, int(totloop)*4)
+	if err != nil {
+		return nil, fmt.Errorf("blend: unable to read custom normals of block '%s' at %#x: %w", b.Header.Code, data, err)
+	}
+	normals := make([][2]int16, totloop)
+	for i := range normals {
+		normals[i] = [2]int16{int16(f.order.Uint16(raw[4*i:])), int16(f.order.Uint16(raw[4*i+2:]))}
+	}
+	return normals, nil
+}
+
+// mvertNormals decodes the normals stored in the MVert array of a mesh.
+// Each component is a short scaled to the range of -32767 to 32767.
+func (f *File) mvertNormals(me *instance) ([][3]float32, error) {
+	mvert, err := me.pointer("mvert")
+	if err != nil {
+		return nil, err
+	}
+	if mvert == 0 {
+		return [][3]float32{}, nil
+	}
+	b, err := f.blockByAddress(mvert)
+	if err != nil {
+		return nil, err
+	}
+	if err := f.checkInstances(b, int(b.Header.Count)); err != nil {
+		return nil, err
+	}
+	normals := make([][3]float32, b.Header.Count)
+	for i := range normals {
+		v, err := f.blockInstance(b, i)
+		if err != nil {
+			return nil, err
+		}
+		_, raw, err := v.field("no")
+		if err != nil {
+			return nil, err
+		}
+		for j := range normals[i] {
+			n, err := f.decodeInt("short", raw[2*j:])
+			if err != nil {
+				return nil, err
+			}
+			normals[i][j] = float32(n) / 32767
+		}
+	}
+	return normals, nil
+}
@@ -0,0 +1,428 @@
+package blend
+
+import (
+	"errors"
+	"math"
+	"strings"
+	"testing"
+)
+
+func TestFile_ExtractMesh(t *testing.T) {
+	fx := newFixture(t)
+	f := fx.f
+	cube := fx.blockNamed("OB", "OBCube")
+
+	mesh, err := f.ExtractMesh(cube.Header.OldMemoryAddress)
+	if err != nil {
+		t.Fatalf("Expected nil error, got: %v", err)
+	}
+	if len(mesh.Vertices) != 8 || len(mesh.Normals) != 8 {
+		t.Errorf("expected 8 vertices and normals, got %d and %d", len(mesh.Vertices), len(mesh.Normals))
+	}
+	if len(mesh.Faces) != 6 || len(mesh.UVs) != 24 {
+		t.Fatalf("expected 6 faces and 24 uvs, got %d and %d", len(mesh.Faces), len(mesh.UVs))
+	}
+	for i, face := range mesh.Faces {
+		if len(face) != 4 {
+			t.Errorf("expected quad at face %d, got: %v", i, face)
+		}
+		for _, v := range face {
+			if v < 0 || v >= len(mesh.Vertices) {
+				t.Errorf("expected vertex index of face %d in range, got %d", i, v)
+			}
+		}
+	}
+	for _, v := range mesh.Vertices {
+		for _, c := range v {
+			if math.Abs(math.Abs(float64(c))-1) > 0.01 {
+				t.Errorf("expected vertex of a cube of size 2, got: %v", v)
+			}
+		}
+	}
+
+	camera := fx.blockNamed("OB", "OBCamera")
+	if _, err := f.ExtractMesh(camera.Header.OldMemoryAddress); err == nil {
+		t.Error("expected error extracting the mesh of a camera")
+	}
+}
+
+func TestFile_MeshVerticesShortField(t *testing.T) {
+	fx := newFixture(t)
+	sdna := *fx.f.sdna
+	sdna.Names = append([]string{}, sdna.Names...)
+	for i, name := range sdna.Names {
+		if name == "co[3]" {
+			// a malformed SDNA declaring positions of two floats only
+			sdna.Names[i] = "co[2]"
+		}
+	}
+	dna := fx.block(CodeDNA1, 0)
+	dna.Data = encodeSDNA(fx.f.order, &sdna)
+	dna.Header.Size = uint32(len(dna.Data))
+
+	if _, err := fx.file().MeshVertices(fx.block(CodeMesh, 0).Header.OldMemoryAddress); err == nil {
+		t.Error("expected error for vertex positions of fewer than three floats")
+	}
+}
+
+func TestFile_MeshUVs(t *testing.T) {
+	f := readExampleFile(t, "cubus-animated.blend")
+	me := f.fileBlocks["ME"][0].Header.OldMemoryAddress
+
+	for _, name := range []string{"", "UVMap"} {
+		uvs, err := f.MeshUVs(me, name)
+		if err != nil {
+			t.Fatalf("Expected nil error, got: %v", err)
+		}
+		if len(uvs) != 24 {
+			t.Fatalf("expected 24 uvs, got %d", len(uvs))
+		}
+		if uvs[0] != [2]float32{0.375, 0} || uvs[23] != [2]float32{0.625, 0.75} {
+			t.Errorf("expected uvs of the unwrapped cube, got: %v", uvs)
+		}
+	}
+
+	if _, err := f.MeshUVs(me, "Missing"); err == nil {
+		t.Error("expected error for unknown uv map")
+	}
+}
+
+func TestFile_MeshUVsActiveLayer(t *testing.T) {
+	fx := newFixture(t)
+	me := fx.blockNamed("ME", "MECube")
+	in, err := fx.f.blockInstance(me, 0)
+	if err != nil {
+		t.Fatalf("Expected nil error, got: %v", err)
+	}
+	addr, err := in.pointer("ldata.layers")
+	if err != nil {
+		t.Fatalf("Expected nil error, got: %v", err)
+	}
+	old := fx.f.addresses[addr]
+	layers := fx.add("DATA", "CustomDataLayer", 3)
+	copy(layers.Data, old.Data)
+	uvs := fx.add("DATA", "MLoopUV", 24)
+	for i := 0; i < 24; i++ {
+		fx.set(uvs, i, "uv", []float32{float32(i), 1})
+	}
+	fx.set(layers, 2, "type", cdMLoopUV)
+	fx.set(layers, 2, "name", "Second")
+	fx.set(layers, 2, "data", uvs.Header.OldMemoryAddress)
+	fx.set(layers, 0, "active", 1)
+	fx.set(layers, 2, "active", 1)
+	fx.set(me, 0, "ldata.layers", layers.Header.OldMemoryAddress)
+	fx.set(me, 0, "ldata.totlayer", 3)
+
+	f := fx.file()
+	for _, name := range []string{"", "Second"} {
+		uvs, err := f.MeshUVs(me.Header.OldMemoryAddress, name)
+		if err != nil {
+			t.Fatalf("Expected nil error, got: %v", err)
+		}
+		if len(uvs) != 24 || uvs[5] != [2]float32{5, 1} {
+			t.Errorf("expected uvs of the second uv map, got: %v", uvs)
+		}
+	}
+}
+
+func TestFile_MeshVertexColors(t *testing.T) {
+	fx := newFixture(t)
+	me := fx.blockNamed("ME", "MECube")
+	bytesRaw := make([]byte, 24*4)
+	floatRaw := make([]byte, 24*16)
+	for i := 0; i < 24; i++ {
+		copy(bytesRaw[4*i:], []byte{uint8(i), 128, 255, 255})
+		for j, v := range []float32{0, 0.5, 1, 0.5} {
+			fx.f.order.PutUint32(floatRaw[16*i+4*j:], math.Float32bits(v))
+		}
+	}
+	byteColors := fx.addRaw("DATA", 0, 1, bytesRaw)
+	floatColors := fx.addRaw("DATA", 0, 1, floatRaw)
+	layers := fx.add("DATA", "CustomDataLayer", 2)
+	fx.set(layers, 0, "type", cdPropByteColor)
+	fx.set(layers, 0, "name", "Col")
+	fx.set(layers, 0, "data", byteColors.Header.OldMemoryAddress)
+	fx.set(layers, 1, "type", cdPropColor)
+	fx.set(layers, 1, "name", "Attribute")
+	fx.set(layers, 1, "data", floatColors.Header.OldMemoryAddress)
+	fx.set(me, 0, "ldata.layers", layers.Header.OldMemoryAddress)
+	fx.set(me, 0, "ldata.totlayer", 2)
+	f := fx.file()
+
+	for _, name := range []string{"", "Col"} {
+		colors, err := f.MeshVertexColors(me.Header.OldMemoryAddress, name)
+		if err != nil {
+			t.Fatalf("Expected nil error, got: %v", err)
+		}
+		if len(colors) != 24 || colors[5] != [4]uint8{5, 128, 255, 255} {
+			t.Errorf("expected byte colors of layer 'Col', got: %v", colors)
+		}
+	}
+	colors, err := f.MeshVertexColors(me.Header.OldMemoryAddress, "Attribute")
+	if err != nil {
+		t.Fatalf("Expected nil error, got: %v", err)
+	}
+	// linear 0.5 is 188 in sRGB while alpha stays linear
+	if len(colors) != 24 || colors[0] != [4]uint8{0, 188, 255, 128} {
+		t.Errorf("expected float colors converted to sRGB, got: %v", colors)
+	}
+
+	if _, err := f.MeshVertexColors(me.Header.OldMemoryAddress, "Missing"); err == nil {
+		t.Error("expected error for unknown color layer")
+	}
+	if _, err := readExampleFile(t, "cubus-animated.blend").MeshVertexColors(me.Header.OldMemoryAddress, ""); err == nil || !strings.Contains(err.Error(), "no vertex colors") {
+		t.Errorf("expected error for mesh without vertex colors, got: %v", err)
+	}
+}
+
+func TestFile_MeshCustomNormals(t *testing.T) {
+	fx := newFixture(t)
+	me := fx.blockNamed("ME", "MECube")
+	raw := make([]byte, 24*12)
+	for i := 0; i < 24; i++ {
+		fx.f.order.PutUint32(raw[12*i+4*(i%3):], math.Float32bits(1))
+	}
+	data := fx.addRaw("DATA", 0, 1, raw)
+	layers := fx.add("DATA", "CustomDataLayer", 1)
+	fx.set(layers, 0, "type", cdPropFloat3)
+	fx.set(layers, 0, "name", "custom_normal")
+	fx.set(layers, 0, "data", data.Header.OldMemoryAddress)
+	fx.set(me, 0, "ldata.layers", layers.Header.OldMemoryAddress)
+	fx.set(me, 0, "ldata.totlayer", 1)
+
+	normals, err := fx.file().MeshCustomNormals(me.Header.OldMemoryAddress)
+	if err != nil {
+		t.Fatalf("Expected nil error, got: %v", err)
+	}
+	if len(normals) != 24 || normals[0] != [3]float32{1, 0, 0} || normals[23] != [3]float32{0, 0, 1} {
+		t.Errorf("expected 24 normals along the axes, got: %v", normals)
+	}
+
+	fx.set(layers, 0, "type", cdCustomLoopNormal)
+	if _, err := fx.file().MeshCustomNormals(me.Header.OldMemoryAddress); err == nil || errors.Is(err, ErrNoCustomNormals) {
+		t.Errorf("expected error for custom normals relative to loop normal spaces, got: %v", err)
+	}
+
+	f := readExampleFile(t, "cubus-animated.blend")
+	if _, err := f.MeshCustomNormals(me.Header.OldMemoryAddress); !errors.Is(err, ErrNoCustomNormals) {
+		t.Errorf("expected ErrNoCustomNormals, got: %v", err)
+	}
+}
+
+func TestFile_MeshCustomLoopNormals(t *testing.T) {
+	fx := newFixture(t)
+	me := fx.blockNamed("ME", "MECube")
+	raw := make([]byte, 24*4)
+	beta := int16(-32767)
+	for i := 0; i < 24; i++ {
+		fx.f.order.PutUint16(raw[4*i:], uint16(int16(i)))
+		fx.f.order.PutUint16(raw[4*i+2:], uint16(beta))
+	}
+	data := fx.addRaw("DATA", 0, 1, raw)
+	layers := fx.add("DATA", "CustomDataLayer", 1)
+	fx.set(layers, 0, "type", cdCustomLoopNormal)
+	fx.set(layers, 0, "data", data.Header.OldMemoryAddress)
+	fx.set(me, 0, "ldata.layers", layers.Header.OldMemoryAddress)
+	fx.set(me, 0, "ldata.totlayer", 1)
+
+	normals, err := fx.file().MeshCustomLoopNormals(me.Header.OldMemoryAddress)
+	if err != nil {
+		t.Fatalf("Expected nil error, got: %v", err)
+	}
+	if len(normals) != 24 || normals[0] != [2]int16{0, -32767} || normals[23] != [2]int16{23, -32767} {
+		t.Errorf("expected 24 normals in loop normal space, got: %v", normals)
+	}
+
+	f := readExampleFile(t, "cubus-animated.blend")
+	if _, err := f.MeshCustomLoopNormals(me.Header.OldMemoryAddress); !errors.Is(err, ErrNoCustomNormals) {
+		t.Errorf("expected ErrNoCustomNormals, got: %v", err)
+	}
+}
+
+func TestFile_MeshUVsNone(t *testing.T) {
+	fx := newFixture(t)
+	me := fx.blockNamed("ME", "MECube")
+	fx.set(me, 0, "ldata.totlayer", 0)
+
+	_, err := fx.file().MeshUVs(me.Header.OldMemoryAddress, "")
+	if err == nil || !strings.Contains(err.Error(), "no uv maps") {
+		t.Errorf("expected error for mesh without uv maps, got: %v", err)
+	}
+}
+
+func TestFile_MeshUVsAttributes(t *testing.T) {
+	fx := newFixture(t)
+	me := fx.blockNamed("ME", "MECube")
+	// files since Blender 3.5 no longer contain the MLoopUV struct
+	fx.renameType("MLoopUV", "MLoopUVLegacy")
+
+	_, err := fx.file().MeshUVs(me.Header.OldMemoryAddress, "")
+	if err == nil || !strings.Contains(err.Error(), "not supported") {
+		t.Errorf("expected unsupported error, got: %v", err)
+	}
+}
+
+func TestFile_MeshNormals(t *testing.T) {
+	f := readExampleFile(t, "cubus-animated.blend")
+	me := f.fileBlocks["ME"][0].Header.OldMemoryAddress
+
+	normals, err := f.MeshNormals(me)
+	if err != nil {
+		t.Fatalf("Expected nil error, got: %v", err)
+	}
+	if len(normals) != 8 {
+		t.Fatalf("expected 8 normals, got %d", len(normals))
+	}
+	for i, n := range normals {
+		length := math.Sqrt(float64(n[0]*n[0] + n[1]*n[1] + n[2]*n[2]))
+		if math.Abs(length-1) > 0.001 {
+			t.Errorf("expected unit normal for vertex %d, got %v with length %f", i, n, length)
+		}
+	}
+}
+
+func TestFile_MeshNormalsLayer(t *testing.T) {
+	fx := newFixture(t)
+	me := fx.blockNamed("ME", "MECube")
+	raw := make([]byte, 8*12)
+	for i := 0; i < 8; i++ {
+		fx.f.order.PutUint32(raw[12*i+8:], math.Float32bits(1))
+	}
+	data := fx.addRaw("DATA", 0, 1, raw)
+	layers := fx.add("DATA", "CustomDataLayer", 1)
+	fx.set(layers, 0, "type", cdNormal)
+	fx.set(layers, 0, "data", data.Header.OldMemoryAddress)
+	fx.set(me, 0, "vdata.layers", layers.Header.OldMemoryAddress)
+	fx.set(me, 0, "vdata.totlayer", 1)
+	// files without MVert store positions and normals as layers
+	fx.renameType("MVert", "MVertLegacy")
+
+	normals, err := fx.file().MeshNormals(me.Header.OldMemoryAddress)
+	if err != nil {
+		t.Fatalf("Expected nil error, got: %v", err)
+	}
+	if len(normals) != 8 || normals[7] != [3]float32{0, 0, 1} {
+		t.Errorf("expected 8 normals along z, got: %v", normals)
+	}
+}
+
+func TestFile_MeshMaterials(t *testing.T) {
+	f := readExampleFile(t, "cubus-animated.blend")
+	me := f.fileBlocks["ME"][0].Header.OldMemoryAddress
+
+	materials, err := f.MeshMaterials(me)
+	if err != nil {
+		t.Fatalf("Expected nil error, got: %v", err)
+	}
+	if len(materials) != 1 || materials[0] != "Material" {
+		t.Errorf("expected material 'Material', got: %v", materials)
+	}
+	indices, err := f.MeshMaterialIndices(me)
+	if err != nil {
+		t.Fatalf("Expected nil error, got: %v", err)
+	}
+	if len(indices) != 6 {
+		t.Fatalf("expected 6 material indices, got %d", len(indices))
+	}
+	for i, index := range indices {
+		if index != 0 {
+			t.Errorf("expected material index 0 for face %d, got %d", i, index)
+		}
+	}
+}
+
+func TestFile_MeshMaterialsMultiple(t *testing.T) {
+	fx := newFixture(t)
+	me := fx.blockNamed("ME", "MECube")
+	in, err := fx.f.blockInstance(me, 0)
+	if err != nil {
+		t.Fatalf("Expected nil error, got: %v", err)
+	}
+	mpoly, err := in.pointer("mpoly")
+	if err != nil {
+		t.Fatalf("Expected nil error, got: %v", err)
+	}
+	second := fx.add("MA", "Material", 1)
+	fx.set(second, 0, "id.name", "MASecond")
+	pointers := make([]byte, 16)
+	fx.f.order.PutUint64(pointers, fx.blockNamed("MA", "MAMaterial").Header.OldMemoryAddress)
+	fx.f.order.PutUint64(pointers[8:], second.Header.OldMemoryAddress)
+	mat := fx.addRaw("DATA", 0, 1, pointers)
+	fx.set(me, 0, "mat", mat.Header.OldMemoryAddress)
+	fx.set(me, 0, "totcol", 2)
+	polys := fx.f.addresses[mpoly]
+	fx.set(polys, 1, "mat_nr", 1)
+	fx.set(polys, 4, "mat_nr", 1)
+
+	f := fx.file()
+	materials, err := f.MeshMaterials(me.Header.OldMemoryAddress)
+	if err != nil {
+		t.Fatalf("Expected nil error, got: %v", err)
+	}
+	if len(materials) != 2 || materials[0] != "Material" || materials[1] != "Second" {
+		t.Errorf("expected materials [Material Second], got: %v", materials)
+	}
+	indices, err := f.MeshMaterialIndices(me.Header.OldMemoryAddress)
+	if err != nil {
+		t.Fatalf("Expected nil error, got: %v", err)
+	}
+	expected := []int{0, 1, 0, 0, 1, 0}
+	if len(indices) != len(expected) {
+		t.Fatalf("expected material indices %v, got: %v", expected, indices)
+	}
+	for i := range expected {
+		if indices[i] != expected[i] {
+			t.Errorf("expected material indices %v, got: %v", expected, indices)
+			break
+		}
+	}
+}
+
+func TestFile_MeshEdges(t *testing.T) {
+	fx := newFixture(t)
+	me := fx.blockNamed("ME", "MECube")
+	in, err := fx.f.blockInstance(me, 0)
+	if err != nil {
+		t.Fatalf("Expected nil error, got: %v", err)
+	}
+	medge, err := in.pointer("medge")
+	if err != nil {
+		t.Fatalf("Expected nil error, got: %v", err)
+	}
+	edges := fx.f.addresses[medge]
+	fx.set(edges, 3, "flag", meSeam)
+	fx.set(edges, 5, "flag", meSeam|meSharp)
+	fx.set(edges, 5, "crease", 255)
+
+	result, err := fx.file().MeshEdges(me.Header.OldMemoryAddress)
+	if err != nil {
+		t.Fatalf("Expected nil error, got: %v", err)
+	}
+	if len(result) != 12 {
+		t.Fatalf("expected 12 edges, got %d", len(result))
+	}
+	for i, e := range result {
+		if e.V1 == e.V2 || e.V1 < 0 || e.V1 >= 8 || e.V2 < 0 || e.V2 >= 8 {
+			t.Errorf("expected edge %d to connect two of the 8 vertices, got: %+v", i, e)
+		}
+		seam, sharp, crease := i == 3 || i == 5, i == 5, float32(0)
+		if i == 5 {
+			crease = 1
+		}
+		if e.Seam != seam || e.Sharp != sharp || e.Crease != crease {
+			t.Errorf("expected seam %v, sharp %v and crease %v for edge %d, got: %+v", seam, sharp, crease, i, e)
+		}
+	}
+}
+
+func TestFile_MeshEdgesAttributes(t *testing.T) {
+	fx := newFixture(t)
+	me := fx.blockNamed("ME", "MECube")
+	// files since Blender 3.6 no longer contain the MEdge struct
+	fx.renameType("MEdge", "MEdgeLegacy")
+
+	_, err := fx.file().MeshEdges(me.Header.OldMemoryAddress)
+	if err == nil || !strings.Contains(err.Error(), "not supported") {
+		t.Errorf("expected unsupported error, got: %v", err)
+	}
+}
@@ -0,0 +1,40 @@
+package blend
+
+import "time"
+
+// ParseMetrics holds the time spent in each phase of parsing a file along with the amount of data read,
+// as recorded when the File was created with WithMetrics.
+type ParseMetrics struct {
+	// Time spent reading the file header
+	Header time.Duration
+	// Time spent reading file-blocks, including the time spent waiting for the underlying reader
+	Blocks time.Duration
+	// Time spent parsing the SDNA
+	SDNA time.Duration
+	// Number of file-blocks read, including the terminating 'ENDB' block
+	BlocksRead int
+	// Number of bytes read from the file, excluding data following the 'ENDB' block
+	BytesRead int64
+}
+
+// Metrics returns the metrics recorded so far, which are all zero unless WithMetrics is given.
+// Durations accumulate if blocks are read repeatedly, e.g. after Rewind.
+func (f *File) Metrics() ParseMetrics {
+	if f.metrics == nil {
+		return ParseMetrics{}
+	}
+	return *f.metrics
+}
+
+// countBlock records a block read completely, which started at offset, if metrics are enabled.
+func (f *File) countBlock(offset int64) {
+	if f.metrics != nil {
+		f.metrics.BlocksRead++
+		f.metrics.BytesRead += f.offset() - offset
+	}
+}
+
+// track adds the time passed since start to d. It is meant to be deferred at the start of a phase.
+func track(d *time.Duration, start time.Time) {
+	*d += time.Since(start)
+}
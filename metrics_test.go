@@ -0,0 +1,52 @@
+package blend
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestWithMetrics(t *testing.T) {
+	data := newFixture(t).bytes()
+	f := parseFile(t, data, WithMetrics())
+	if _, err := f.SDNA(); err != nil {
+		t.Fatalf("Expected nil error, got: %v", err)
+	}
+
+	m := f.Metrics()
+	if m.Header < 0 || m.Blocks < 0 || m.SDNA < 0 {
+		t.Errorf("expected non-negative durations, got: %+v", m)
+	}
+	if m.BlocksRead != len(f.blocks) {
+		t.Errorf("expected %d blocks read, got %d", len(f.blocks), m.BlocksRead)
+	}
+	if m.BytesRead != int64(len(data)) {
+		t.Errorf("expected %d bytes read, got %d", len(data), m.BytesRead)
+	}
+}
+
+func TestFile_MetricsDisabled(t *testing.T) {
+	f := parseFile(t, newFixture(t).bytes())
+	if _, err := f.SDNA(); err != nil {
+		t.Fatalf("Expected nil error, got: %v", err)
+	}
+	if m := f.Metrics(); m != (ParseMetrics{}) {
+		t.Errorf("expected no metrics without WithMetrics, got: %+v", m)
+	}
+}
+
+func TestWithMetrics_blockReader(t *testing.T) {
+	data := newFixture(t).bytes()
+	f, err := NewFile(bytes.NewReader(data), WithMetrics())
+	if err != nil {
+		t.Fatalf("Expected nil error, got: %v", err)
+	}
+	br := f.BlockReader()
+	for {
+		if _, err := br.Next(); err != nil {
+			break
+		}
+	}
+	if m := f.Metrics(); m.BytesRead != int64(len(data)) {
+		t.Errorf("expected %d bytes read, got %d", len(data), m.BytesRead)
+	}
+}
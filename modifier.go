@@ -0,0 +1,126 @@
+package blend
+
+import "fmt"
+
+// Modifier is a single entry of the modifier stack of an object.
+type Modifier struct {
+	// Raw value of the `type` field
+	Type int
+	// Name of the modifier type as used by the Python API, e.g. SUBSURF
+	TypeName string
+	// Name of the modifier shown in the user interface
+	Name string
+}
+
+// modifierTypeNames maps the values of Blender's ModifierType enum to the names used by the Python API.
+var modifierTypeNames = map[int]string{
+	1:  "SUBSURF",
+	2:  "LATTICE",
+	3:  "CURVE",
+	4:  "BUILD",
+	5:  "MIRROR",
+	6:  "DECIMATE",
+	7:  "WAVE",
+	8:  "ARMATURE",
+	9:  "HOOK",
+	10: "SOFT_BODY",
+	11: "BOOLEAN",
+	12: "ARRAY",
+	13: "EDGE_SPLIT",
+	14: "DISPLACE",
+	15: "UV_PROJECT",
+	16: "SMOOTH",
+	17: "CAST",
+	18: "MESH_DEFORM",
+	19: "PARTICLE_SYSTEM",
+	20: "PARTICLE_INSTANCE",
+	21: "EXPLODE",
+	22: "CLOTH",
+	23: "COLLISION",
+	24: "BEVEL",
+	25: "SHRINKWRAP",
+	26: "FLUID_SIMULATION",
+	27: "MASK",
+	28: "SIMPLE_DEFORM",
+	29: "MULTIRES",
+	30: "SURFACE",
+	31: "SMOKE",
+	33: "SOLIDIFY",
+	34: "SCREW",
+	35: "WARP",
+	36: "VERTEX_WEIGHT_EDIT",
+	37: "VERTEX_WEIGHT_MIX",
+	38: "VERTEX_WEIGHT_PROXIMITY",
+	39: "OCEAN",
+	40: "DYNAMIC_PAINT",
+	41: "REMESH",
+	42: "SKIN",
+	43: "LAPLACIANSMOOTH",
+	44: "TRIANGULATE",
+	45: "UV_WARP",
+	46: "MESH_CACHE",
+	47: "LAPLACIANDEFORM",
+	48: "WIREFRAME",
+	49: "DATA_TRANSFER",
+	50: "NORMAL_EDIT",
+	51: "CORRECTIVE_SMOOTH",
+	52: "MESH_SEQUENCE_CACHE",
+	53: "SURFACE_DEFORM",
+	54: "WEIGHTED_NORMAL",
+	55: "WELD",
+	56: "FLUID",
+	57: "NODES",
+	58: "MESH_TO_VOLUME",
+	59: "VOLUME_DISPLACE",
+	60: "VOLUME_TO_MESH",
+}
+
+// ModifierTypeName returns the name of a modifier's `type`, e.g. SUBSURF, or "UNKNOWN".
+func ModifierTypeName(t int) string {
+	if name, ok := modifierTypeNames[t]; ok {
+		return name
+	}
+	return "UNKNOWN"
+}
+
+// Modifiers decodes the modifier stack of the object located at objectAddr in evaluation order.
+// Objects without modifiers return an empty slice.
+func (f *File) Modifiers(objectAddr uint64) ([]Modifier, error) {
+	ob, err := f.structAt(objectAddr, "Object")
+	if err != nil {
+		return nil, err
+	}
+	first, err := ob.pointer("modifiers.first")
+	if err != nil {
+		return nil, err
+	}
+
+	modifiers := []Modifier{}
+	err = f.walkList(first, func(md *instance) error {
+		// every modifier struct starts with an embedded ModifierData
+		if md.typeName() != "ModifierData" {
+			var err error
+			if md, err = md.sub("modifier"); err != nil {
+				return err
+			}
+		}
+		t, err := md.int("type")
+		if err != nil {
+			return err
+		}
+		name, err := md.string("name")
+		if err != nil {
+			return err
+		}
+		modifiers = append(modifiers, Modifier{
+			Type:     int(t),
+			TypeName: ModifierTypeName(int(t)),
+			Name:     name,
+		})
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("blend: unable to read modifiers of object at %#x: %w", objectAddr, err)
+	}
+	return modifiers, nil
+}
@@ -0,0 +1,63 @@
+package blend
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestModifierTypeName(t *testing.T) {
+	testTable := map[int]string{
+		1:  "SUBSURF",
+		5:  "MIRROR",
+		12: "ARRAY",
+		54: "WEIGHTED_NORMAL",
+		57: "NODES",
+		0:  "UNKNOWN",
+		32: "UNKNOWN",
+	}
+	for typ, expected := range testTable {
+		if name := ModifierTypeName(typ); name != expected {
+			t.Errorf("expected %q for type %d, got %q", expected, typ, name)
+		}
+	}
+}
+
+func TestFile_Modifiers(t *testing.T) {
+	fx := newFixture(t)
+	ob := fx.blockNamed("OB", "OBCube")
+	subsurf := fx.add("DATA", "SubsurfModifierData", 1)
+	fx.set(subsurf, 0, "modifier.type", 1)
+	fx.set(subsurf, 0, "modifier.name", "Subdivision")
+	mirror := fx.add("DATA", "MirrorModifierData", 1)
+	fx.set(mirror, 0, "modifier.type", 5)
+	fx.set(mirror, 0, "modifier.name", "Mirror")
+	fx.set(subsurf, 0, "modifier.next", mirror.Header.OldMemoryAddress)
+	fx.set(ob, 0, "modifiers.first", subsurf.Header.OldMemoryAddress)
+	fx.set(ob, 0, "modifiers.last", mirror.Header.OldMemoryAddress)
+
+	modifiers, err := fx.file().Modifiers(ob.Header.OldMemoryAddress)
+	if err != nil {
+		t.Fatalf("Expected nil error, got: %v", err)
+	}
+	expected := []Modifier{
+		{Type: 1, TypeName: "SUBSURF", Name: "Subdivision"},
+		{Type: 5, TypeName: "MIRROR", Name: "Mirror"},
+	}
+	if !reflect.DeepEqual(modifiers, expected) {
+		t.Errorf("expected %+v, got: %+v", expected, modifiers)
+	}
+}
+
+func TestFile_ModifiersEmpty(t *testing.T) {
+	f := readExampleFile(t, "cubus-animated.blend")
+
+	for _, b := range f.fileBlocks["OB"] {
+		modifiers, err := f.Modifiers(b.Header.OldMemoryAddress)
+		if err != nil {
+			t.Fatalf("Expected nil error, got: %v", err)
+		}
+		if modifiers == nil || len(modifiers) != 0 {
+			t.Errorf("expected empty modifier stack, got: %v", modifiers)
+		}
+	}
+}
@@ -0,0 +1,165 @@
+package blend
+
+import "fmt"
+
+// maxNodeGroupDepth bounds the nesting of node groups, which protects against groups containing themselves.
+const maxNodeGroupDepth = 32
+
+// NodeTree is a node tree, like the shader nodes of a material or world, with its nodes and the links between them.
+type NodeTree struct {
+	// Name of the tree without its ID code
+	Name string
+	// Type of the tree as used by the Python API, e.g. ShaderNodeTree
+	Type string
+	// Nodes of the tree in the order they are stored
+	Nodes []Node
+	// Links between sockets of the nodes
+	Links []NodeLink
+}
+
+// Node is a single node of a node tree.
+type Node struct {
+	// Type of the node as used by the Python API, e.g. ShaderNodeBsdfPrincipled
+	Type string
+	// Unique name of the node within its tree
+	Name string
+	// Address of the datablock the node uses, like the image of an image texture node, or 0
+	ID uint64
+	// Tree used by a group node, nil for other nodes
+	Group *NodeTree
+}
+
+// NodeLink connects an output socket of a node to an input socket of another node.
+// Sockets are identified by their identifier, which unlike their name is unique among the sockets of a node.
+type NodeLink struct {
+	FromNode   string
+	FromSocket string
+	ToNode     string
+	ToSocket   string
+}
+
+// NodeTree decodes the node tree located at addr, e.g. the `nodetree` of a material, along with the trees of its group nodes.
+// A group tree used by several group nodes is decoded once, with each of them referencing the same tree.
+func (f *File) NodeTree(addr uint64) (*NodeTree, error) {
+	return f.nodeTree(addr, 0, make(map[uint64]*NodeTree))
+}
+
+// nodeTree decodes the node tree at addr, which is nested in depth node groups.
+// Group trees already decoded are taken from groups.
+func (f *File) nodeTree(addr uint64, depth int, groups map[uint64]*NodeTree) (*NodeTree, error) {
+	if depth > maxNodeGroupDepth {
+		return nil, fmt.Errorf("blend: node groups nested deeper than %d levels at %#x", maxNodeGroupDepth, addr)
+	}
+	if tree, ok := groups[addr]; ok {
+		return tree, nil
+	}
+	ntree, err := f.structAt(addr, "bNodeTree")
+	if err != nil {
+		return nil, err
+	}
+	name, err := ntree.idName()
+	if err != nil {
+		return nil, err
+	}
+	idname, err := ntree.string("idname")
+	if err != nil {
+		return nil, err
+	}
+	tree := &NodeTree{Name: name, Type: idname}
+
+	first, err := ntree.pointer("nodes.first")
+	if err != nil {
+		return nil, err
+	}
+	err = f.walkList(first, func(n *instance) error {
+		node, err := f.decodeNode(n, depth, groups)
+		if err != nil {
+			return err
+		}
+		tree.Nodes = append(tree.Nodes, node)
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("blend: unable to read nodes of node tree at %#x: %w", addr, err)
+	}
+
+	first, err = ntree.pointer("links.first")
+	if err != nil {
+		return nil, err
+	}
+	err = f.walkList(first, func(l *instance) error {
+		link, err := f.decodeNodeLink(l)
+		if err != nil {
+			return err
+		}
+		tree.Links = append(tree.Links, link)
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("blend: unable to read links of node tree at %#x: %w", addr, err)
+	}
+	groups[addr] = tree
+	return tree, nil
+}
+
+// decodeNode decodes a node of a tree nested in depth node groups, following the tree of group nodes.
+func (f *File) decodeNode(n *instance, depth int, groups map[uint64]*NodeTree) (Node, error) {
+	idname, err := n.string("idname")
+	if err != nil {
+		return Node{}, err
+	}
+	name, err := n.string("name")
+	if err != nil {
+		return Node{}, err
+	}
+	node := Node{Type: idname, Name: name}
+
+	id, err := n.pointer("id")
+	if err != nil {
+		return Node{}, err
+	}
+	if id == 0 {
+		return node, nil
+	}
+	node.ID = id
+	// nodes like image textures reference other kinds of datablocks
+	in, err := f.instanceAt(id)
+	if err != nil {
+		return Node{}, err
+	}
+	if in.typeName() == "bNodeTree" {
+		if node.Group, err = f.nodeTree(id, depth+1, groups); err != nil {
+			return Node{}, err
+		}
+	}
+	return node, nil
+}
+
+// decodeNodeLink resolves the nodes and sockets connected by a link.
+func (f *File) decodeNodeLink(l *instance) (NodeLink, error) {
+	var link NodeLink
+	for _, v := range []struct {
+		path  string
+		typ   string
+		field string
+		dst   *string
+	}{
+		{"fromnode", "bNode", "name", &link.FromNode},
+		{"fromsock", "bNodeSocket", "identifier", &link.FromSocket},
+		{"tonode", "bNode", "name", &link.ToNode},
+		{"tosock", "bNodeSocket", "identifier", &link.ToSocket},
+	} {
+		addr, err := l.pointer(v.path)
+		if err != nil {
+			return NodeLink{}, err
+		}
+		in, err := f.structAt(addr, v.typ)
+		if err != nil {
+			return NodeLink{}, err
+		}
+		if *v.dst, err = in.string(v.field); err != nil {
+			return NodeLink{}, err
+		}
+	}
+	return link, nil
+}
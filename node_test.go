@@ -0,0 +1,107 @@
+package blend
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestFile_NodeTree(t *testing.T) {
+	fx := newFixture(t)
+	ma, err := fx.f.blockInstance(fx.blockNamed(CodeMaterial, "MAMaterial"), 0)
+	if err != nil {
+		t.Fatalf("Expected nil error, got: %v", err)
+	}
+	addr, err := ma.pointer("nodetree")
+	if err != nil {
+		t.Fatalf("Expected nil error, got: %v", err)
+	}
+
+	tree, err := fx.f.NodeTree(addr)
+	if err != nil {
+		t.Fatalf("Expected nil error, got: %v", err)
+	}
+	expected := &NodeTree{
+		Name: "Shader Nodetree",
+		Type: "ShaderNodeTree",
+		Nodes: []Node{
+			{Type: "ShaderNodeOutputMaterial", Name: "Material Output"},
+			{Type: "ShaderNodeBsdfPrincipled", Name: "Principled BSDF"},
+		},
+		Links: []NodeLink{
+			{FromNode: "Principled BSDF", FromSocket: "BSDF", ToNode: "Material Output", ToSocket: "Surface"},
+		},
+	}
+	if !reflect.DeepEqual(tree, expected) {
+		t.Errorf("expected %+v, got: %+v", expected, tree)
+	}
+}
+
+// addGroupNode lets tree hold a single group node using the tree at group and returns the node.
+func addGroupNode(fx *fixture, tree *Block, group uint64) *Block {
+	node := fx.add("DATA", "bNode", 1)
+	fx.set(node, 0, "idname", "ShaderNodeGroup")
+	fx.set(node, 0, "name", "Group")
+	fx.set(node, 0, "id", group)
+	fx.set(tree, 0, "nodes.first", node.Header.OldMemoryAddress)
+	fx.set(tree, 0, "nodes.last", node.Header.OldMemoryAddress)
+	return node
+}
+
+func TestFile_NodeTreeGroup(t *testing.T) {
+	fx := newFixture(t)
+	group := fx.add(CodeNodeTree, "bNodeTree", 1)
+	fx.set(group, 0, "id.name", "NTInner")
+	fx.set(group, 0, "idname", "ShaderNodeTree")
+	tree := fx.add("DATA", "bNodeTree", 1)
+	fx.set(tree, 0, "idname", "ShaderNodeTree")
+	addGroupNode(fx, tree, group.Header.OldMemoryAddress)
+
+	decoded, err := fx.file().NodeTree(tree.Header.OldMemoryAddress)
+	if err != nil {
+		t.Fatalf("Expected nil error, got: %v", err)
+	}
+	expected := &NodeTree{
+		Type: "ShaderNodeTree",
+		Nodes: []Node{{
+			Type:  "ShaderNodeGroup",
+			Name:  "Group",
+			ID:    group.Header.OldMemoryAddress,
+			Group: &NodeTree{Name: "Inner", Type: "ShaderNodeTree"},
+		}},
+	}
+	if !reflect.DeepEqual(decoded, expected) {
+		t.Errorf("expected %+v, got: %+v", expected, decoded)
+	}
+}
+
+func TestFile_NodeTreeRecursiveGroup(t *testing.T) {
+	fx := newFixture(t)
+	group := fx.add(CodeNodeTree, "bNodeTree", 1)
+	addGroupNode(fx, group, group.Header.OldMemoryAddress)
+
+	if _, err := fx.file().NodeTree(group.Header.OldMemoryAddress); err == nil {
+		t.Error("expected error for a node group containing itself")
+	}
+}
+
+func TestFile_NodeTreeSharedGroup(t *testing.T) {
+	fx := newFixture(t)
+	group := fx.add(CodeNodeTree, "bNodeTree", 1)
+	fx.set(group, 0, "idname", "ShaderNodeTree")
+	tree := fx.add("DATA", "bNodeTree", 1)
+	node := addGroupNode(fx, tree, group.Header.OldMemoryAddress)
+	// a second node using the same group
+	second := fx.add("DATA", "bNode", 1)
+	fx.set(second, 0, "idname", "ShaderNodeGroup")
+	fx.set(second, 0, "name", "Group.001")
+	fx.set(second, 0, "id", group.Header.OldMemoryAddress)
+	fx.set(node, 0, "next", second.Header.OldMemoryAddress)
+
+	decoded, err := fx.file().NodeTree(tree.Header.OldMemoryAddress)
+	if err != nil {
+		t.Fatalf("Expected nil error, got: %v", err)
+	}
+	if len(decoded.Nodes) != 2 || decoded.Nodes[0].Group == nil || decoded.Nodes[0].Group != decoded.Nodes[1].Group {
+		t.Errorf("expected both group nodes to share the decoded group, got: %+v", decoded.Nodes)
+	}
+}
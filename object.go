@@ -0,0 +1,168 @@
+package blend
+
+import "fmt"
+
+// objectTypeNames maps the values of an Object's `type` field to the names used by the Python API.
+var objectTypeNames = map[int]string{
+	0:  "EMPTY",
+	1:  "MESH",
+	2:  "CURVE",
+	3:  "SURFACE",
+	4:  "FONT",
+	5:  "META",
+	10: "LIGHT",
+	11: "CAMERA",
+	12: "SPEAKER",
+	13: "LIGHT_PROBE",
+	22: "LATTICE",
+	25: "ARMATURE",
+	26: "GPENCIL",
+	27: "CURVES",
+	28: "POINTCLOUD",
+	29: "VOLUME",
+	30: "GREASEPENCIL",
+}
+
+// objectDataCodes maps object type names to the code of the block their `data` points to.
+var objectDataCodes = map[string]Code{
+	"MESH":         "ME",
+	"CURVE":        "CU",
+	"SURFACE":      "CU",
+	"FONT":         "CU",
+	"META":         "MB",
+	"LIGHT":        "LA",
+	"CAMERA":       "CA",
+	"SPEAKER":      "SK",
+	"LIGHT_PROBE":  "LP",
+	"LATTICE":      "LT",
+	"ARMATURE":     "AR",
+	"GPENCIL":      "GD",
+	"CURVES":       "CV",
+	"POINTCLOUD":   "PT",
+	"VOLUME":       "VO",
+	"GREASEPENCIL": "GP",
+}
+
+// ObjectTypeName returns the name of an Object's `type`, e.g. MESH, or "UNKNOWN".
+func ObjectTypeName(t int) string {
+	if name, ok := objectTypeNames[t]; ok {
+		return name
+	}
+	return "UNKNOWN"
+}
+
+// ObjectData returns the file-block holding the data of the object located at objectAddr, e.g. its Mesh.
+// Objects without data, like empties, return nil.
+func (f *File) ObjectData(objectAddr uint64) (*Block, error) {
+	ob, err := f.structAt(objectAddr, "Object")
+	if err != nil {
+		return nil, err
+	}
+	t, err := ob.int("type")
+	if err != nil {
+		return nil, err
+	}
+	data, err := ob.pointer("data")
+	if err != nil {
+		return nil, err
+	}
+	if data == 0 {
+		return nil, nil
+	}
+
+	typeName := ObjectTypeName(int(t))
+	code, ok := objectDataCodes[typeName]
+	if !ok {
+		return nil, fmt.Errorf("blend: unable to resolve data of object with type %s (%d)", typeName, t)
+	}
+	b, err := f.blockByAddress(data)
+	if err != nil {
+		return nil, err
+	}
+	if b.Header.Code != code {
+		return nil, fmt.Errorf("blend: expected data of %s object in block '%s', got '%s'", typeName, code, b.Header.Code)
+	}
+	return b, nil
+}
+
+// ObjectParents returns the address of the parent of every object by the address of the object.
+// Objects without parent map to 0.
+func (f *File) ObjectParents() (map[uint64]uint64, error) {
+	if _, err := f.SDNA(); err != nil {
+		return nil, err
+	}
+	parents := make(map[uint64]uint64)
+	for _, b := range f.fileBlocks[CodeObject] {
+		parent, err := f.objectParent(b)
+		if err != nil {
+			return nil, err
+		}
+		parents[b.Header.OldMemoryAddress] = parent
+	}
+	return parents, nil
+}
+
+// ObjectChildren returns the addresses of the objects whose parent is the object located at addr, in the order of the file.
+// If addr is 0, the objects without parent are returned.
+func (f *File) ObjectChildren(addr uint64) ([]uint64, error) {
+	if _, err := f.SDNA(); err != nil {
+		return nil, err
+	}
+	if addr != 0 {
+		if _, err := f.structAt(addr, "Object"); err != nil {
+			return nil, err
+		}
+	}
+	children := []uint64{}
+	for _, b := range f.fileBlocks[CodeObject] {
+		parent, err := f.objectParent(b)
+		if err != nil {
+			return nil, err
+		}
+		if parent == addr {
+			children = append(children, b.Header.OldMemoryAddress)
+		}
+	}
+	return children, nil
+}
+
+// objectParent reads the parent of the object stored in b, verifying that it points to an object.
+func (f *File) objectParent(b *Block) (uint64, error) {
+	ob, err := f.blockInstance(b, 0)
+	if err != nil {
+		return 0, err
+	}
+	parent, err := ob.pointer("parent")
+	if err != nil || parent == 0 {
+		return parent, err
+	}
+	p, err := f.blockByAddress(parent)
+	if err != nil {
+		return 0, err
+	}
+	if p.Header.Code != CodeObject {
+		return 0, fmt.Errorf("blend: expected parent of object at %#x in block '%s', got '%s'",
+			b.Header.OldMemoryAddress, CodeObject, p.Header.Code)
+	}
+	return parent, nil
+}
+
+// Flags of the Object `visibility_flag` field, formerly called `restrictflag`.
+const (
+	obHideViewport = 1 << 0
+	obHideRender   = 1 << 2
+)
+
+// ObjectVisibility reports whether the object located at objectAddr is shown in the viewport and in renders.
+// Only the flags of the object itself are considered, not the collections it belongs to or hiding per view layer.
+func (f *File) ObjectVisibility(objectAddr uint64) (viewport, render bool, err error) {
+	ob, err := f.structAt(objectAddr, "Object")
+	if err != nil {
+		return false, false, err
+	}
+	flag, err := ob.int("visibility_flag")
+	if err != nil {
+		return false, false, err
+	}
+	return flag&obHideViewport == 0, flag&obHideRender == 0, nil
+}
@@ -0,0 +1,142 @@
+package blend
+
+import "testing"
+
+func TestObjectTypeName(t *testing.T) {
+	testTable := map[int]string{
+		0:  "EMPTY",
+		1:  "MESH",
+		2:  "CURVE",
+		3:  "SURFACE",
+		4:  "FONT",
+		5:  "META",
+		10: "LIGHT",
+		11: "CAMERA",
+		12: "SPEAKER",
+		13: "LIGHT_PROBE",
+		22: "LATTICE",
+		25: "ARMATURE",
+		26: "GPENCIL",
+		6:  "UNKNOWN",
+		-1: "UNKNOWN",
+	}
+	for typ, expected := range testTable {
+		if name := ObjectTypeName(typ); name != expected {
+			t.Errorf("expected %q for type %d, got %q", expected, typ, name)
+		}
+	}
+}
+
+func TestFile_ObjectData(t *testing.T) {
+	f := readExampleFile(t, "cubus-animated.blend")
+
+	expected := map[string]Code{
+		"OBCube":   "ME",
+		"OBCamera": "CA",
+		"OBLight":  "LA",
+	}
+	for _, b := range f.fileBlocks["OB"] {
+		ob, err := f.blockInstance(b, 0)
+		if err != nil {
+			t.Fatalf("Expected nil error, got: %v", err)
+		}
+		name, _ := ob.string("id.name")
+		data, err := f.ObjectData(b.Header.OldMemoryAddress)
+		if err != nil {
+			t.Fatalf("Expected nil error, got: %v", err)
+		}
+		if data.Header.Code != expected[name] {
+			t.Errorf("expected data of %s in block '%s', got '%s'", name, expected[name], data.Header.Code)
+		}
+		if name == "OBCube" {
+			typ, _ := ob.int("type")
+			if n := ObjectTypeName(int(typ)); n != "MESH" {
+				t.Errorf("expected cube to be of type MESH, got: %s", n)
+			}
+		}
+	}
+}
+
+func TestFile_ObjectDataMismatch(t *testing.T) {
+	fx := newFixture(t)
+	ob := fx.blockNamed("OB", "OBCube")
+	fx.set(ob, 0, "type", 11)
+
+	if _, err := fx.file().ObjectData(ob.Header.OldMemoryAddress); err == nil {
+		t.Error("expected error resolving mesh data of a camera object")
+	}
+}
+
+func TestFile_ObjectParents(t *testing.T) {
+	fx := newFixture(t)
+	camera := fx.blockNamed("OB", "OBCamera").Header.OldMemoryAddress
+	cube := fx.blockNamed("OB", "OBCube")
+	light := fx.blockNamed("OB", "OBLight").Header.OldMemoryAddress
+	fx.set(cube, 0, "parent", camera)
+	f := fx.file()
+
+	parents, err := f.ObjectParents()
+	if err != nil {
+		t.Fatalf("Expected nil error, got: %v", err)
+	}
+	expected := map[uint64]uint64{
+		camera:                       0,
+		cube.Header.OldMemoryAddress: camera,
+		light:                        0,
+	}
+	if len(parents) != len(expected) {
+		t.Fatalf("expected parents %v, got: %v", expected, parents)
+	}
+	for child, parent := range expected {
+		if parents[child] != parent {
+			t.Errorf("expected parent %#x of object %#x, got %#x", parent, child, parents[child])
+		}
+	}
+
+	children, err := f.ObjectChildren(camera)
+	if err != nil {
+		t.Fatalf("Expected nil error, got: %v", err)
+	}
+	if len(children) != 1 || children[0] != cube.Header.OldMemoryAddress {
+		t.Errorf("expected the cube as only child of the camera, got: %v", children)
+	}
+	if children, err := f.ObjectChildren(light); err != nil || len(children) != 0 {
+		t.Errorf("expected no children of the light, got %v: %v", children, err)
+	}
+	if roots, err := f.ObjectChildren(0); err != nil || len(roots) != 2 {
+		t.Errorf("expected camera and light without parent, got %v: %v", roots, err)
+	}
+
+	fx.set(cube, 0, "parent", fx.blockNamed("ME", "MECube").Header.OldMemoryAddress)
+	if _, err := fx.file().ObjectParents(); err == nil {
+		t.Error("expected error for a parent which is no object")
+	}
+}
+
+func TestFile_ObjectVisibility(t *testing.T) {
+	fx := newFixture(t)
+	cube := fx.blockNamed(CodeObject, "OBCube")
+	light := fx.blockNamed(CodeObject, "OBLight")
+	camera := fx.blockNamed(CodeObject, "OBCamera")
+	fx.set(cube, 0, "restrictflag", obHideViewport|obHideRender)
+	fx.set(light, 0, "restrictflag", obHideRender)
+	f := fx.file()
+
+	for _, tt := range []struct {
+		b                *Block
+		viewport, render bool
+	}{
+		{cube, false, false},
+		{light, true, false},
+		{camera, true, true},
+	} {
+		viewport, render, err := f.ObjectVisibility(tt.b.Header.OldMemoryAddress)
+		if err != nil {
+			t.Fatalf("Expected nil error, got: %v", err)
+		}
+		if viewport != tt.viewport || render != tt.render {
+			t.Errorf("expected object at %#x visible in viewport %v and render %v, got %v and %v",
+				tt.b.Header.OldMemoryAddress, tt.viewport, tt.render, viewport, render)
+		}
+	}
+}
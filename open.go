@@ -0,0 +1,86 @@
+package blend
+
+import (
+	"bufio"
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+)
+
+// Open opens the blend file at path, decompressing it if it was saved compressed with gzip.
+// Files compressed with zstd, as done since Blender 3.0, return ErrUnsupportedCompression.
+// The file must be closed with Close.
+func Open(path string, opts ...Option) (*File, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, &OpenError{Kind: KindIO, Err: err}
+	}
+	closers := []io.Closer{file}
+	closeAll := func() {
+		for i := len(closers) - 1; i >= 0; i-- {
+			closers[i].Close()
+		}
+	}
+
+	br := bufio.NewReader(file)
+	_, compression, err := Sniff(br)
+	if err != nil {
+		closeAll()
+		return nil, &OpenError{Kind: KindIO, Err: err}
+	}
+	var r io.Reader = br
+	switch compression {
+	case CompressionGzip:
+		gz, err := gzip.NewReader(br)
+		if err != nil {
+			closeAll()
+			kind := KindIO
+			if errors.Is(err, gzip.ErrHeader) || errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+				kind = KindFormat
+			}
+			return nil, &OpenError{Kind: kind, Err: err}
+		}
+		closers = append(closers, gz)
+		r = gz
+	case CompressionZstd:
+		closeAll()
+		return nil, &OpenError{Kind: KindFormat, Err: fmt.Errorf("%w: %s", ErrUnsupportedCompression, compression)}
+	}
+
+	f, err := NewFile(r, opts...)
+	if err != nil {
+		closeAll()
+		return nil, err
+	}
+	f.closers = closers
+	f.rewind = func() (io.Reader, error) {
+		if _, err := file.Seek(0, io.SeekStart); err != nil {
+			return nil, err
+		}
+		br.Reset(file)
+		if compression != CompressionGzip {
+			return br, nil
+		}
+		gz := r.(*gzip.Reader)
+		if err := gz.Reset(br); err != nil {
+			return nil, err
+		}
+		return gz, nil
+	}
+	return f, nil
+}
+
+// Close closes the underlying file and decompressor if the File was created by Open.
+// Files created by NewFile are left to the caller to close.
+func (f *File) Close() error {
+	var first error
+	for i := len(f.closers) - 1; i >= 0; i-- {
+		if err := f.closers[i].Close(); err != nil && first == nil {
+			first = err
+		}
+	}
+	f.closers = nil
+	return first
+}
@@ -0,0 +1,127 @@
+package blend
+
+import (
+	"compress/gzip"
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestOpen(t *testing.T) {
+	before := openFileDescriptors(t)
+
+	f, err := Open(filepath.Join("./examples", "cubus-animated.blend"))
+	if err != nil {
+		t.Fatalf("Expected nil error, got: %v", err)
+	}
+	if err := f.Validate(); err != nil {
+		t.Errorf("Expected nil error, got: %v", err)
+	}
+	if err := f.Close(); err != nil {
+		t.Errorf("Expected nil error, got: %v", err)
+	}
+	if err := f.Close(); err != nil {
+		t.Errorf("expected repeated Close to succeed, got: %v", err)
+	}
+
+	if after := openFileDescriptors(t); after != before {
+		t.Errorf("expected %d open file descriptors after Close, got %d", before, after)
+	}
+}
+
+func TestOpen_gzip(t *testing.T) {
+	data, err := ioutil.ReadFile(filepath.Join("./examples", "cubus-animated.blend"))
+	if err != nil {
+		t.Fatalf("Expected nil error, got: %v", err)
+	}
+	dir, err := ioutil.TempDir("", "blend")
+	if err != nil {
+		t.Fatalf("Expected nil error, got: %v", err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "compressed.blend")
+	out, err := os.Create(path)
+	if err != nil {
+		t.Fatalf("Expected nil error, got: %v", err)
+	}
+	gz := gzip.NewWriter(out)
+	gz.Write(data)
+	gz.Close()
+	out.Close()
+
+	before := openFileDescriptors(t)
+	f, err := Open(path)
+	if err != nil {
+		t.Fatalf("Expected nil error, got: %v", err)
+	}
+	if err := f.Validate(); err != nil {
+		t.Errorf("Expected nil error, got: %v", err)
+	}
+	if err := f.Rewind(); err != nil {
+		t.Fatalf("Expected nil error, got: %v", err)
+	}
+	if err := f.Validate(); err != nil {
+		t.Errorf("expected rewound file to validate, got: %v", err)
+	}
+	if err := f.Close(); err != nil {
+		t.Errorf("Expected nil error, got: %v", err)
+	}
+	if after := openFileDescriptors(t); after != before {
+		t.Errorf("expected %d open file descriptors after Close, got %d", before, after)
+	}
+}
+
+func TestOpen_zstd(t *testing.T) {
+	dir, err := ioutil.TempDir("", "blend")
+	if err != nil {
+		t.Fatalf("Expected nil error, got: %v", err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "compressed.blend")
+	if err := ioutil.WriteFile(path, []byte{0x28, 0xb5, 0x2f, 0xfd, 0, 0, 0, 0, 0, 0, 0, 0}, 0644); err != nil {
+		t.Fatalf("Expected nil error, got: %v", err)
+	}
+
+	before := openFileDescriptors(t)
+	_, err = Open(path)
+	if !errors.Is(err, ErrUnsupportedCompression) {
+		t.Errorf("expected ErrUnsupportedCompression, got: %v", err)
+	}
+	var openErr *OpenError
+	if !errors.As(err, &openErr) || openErr.Kind != KindFormat {
+		t.Errorf("expected OpenError of kind format, got: %v", err)
+	}
+	if after := openFileDescriptors(t); after != before {
+		t.Errorf("expected %d open file descriptors after failed Open, got %d", before, after)
+	}
+}
+
+func TestOpen_missing(t *testing.T) {
+	_, err := Open(filepath.Join("./examples", "missing.blend"))
+	if !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("expected os.ErrNotExist, got: %v", err)
+	}
+	var openErr *OpenError
+	if !errors.As(err, &openErr) || openErr.Kind != KindIO {
+		t.Errorf("expected OpenError of kind io, got: %v", err)
+	}
+}
+
+func TestFile_CloseNewFile(t *testing.T) {
+	f := readExampleFile(t, "cubus-animated.blend")
+	if err := f.Close(); err != nil {
+		t.Errorf("Expected nil error, got: %v", err)
+	}
+}
+
+// openFileDescriptors returns the number of file descriptors open by the process.
+func openFileDescriptors(t *testing.T) int {
+	t.Helper()
+	fds, err := ioutil.ReadDir("/proc/self/fd")
+	if err != nil {
+		t.Skip("unable to count open file descriptors:", err)
+	}
+	return len(fds)
+}
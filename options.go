@@ -0,0 +1,95 @@
+package blend
+
+// Option configures how a File is read.
+type Option func(*File)
+
+// WithStrictValidation enables additional consistency checks while reading the file,
+// returning an error at the first inconsistency instead of reading on.
+func WithStrictValidation() Option {
+	return func(f *File) {
+		f.strict = true
+	}
+}
+
+// WithDecodeCache sets whether decoded blocks are kept and returned again when the same block is decoded repeatedly.
+// The cache is enabled by default.
+func WithDecodeCache(enabled bool) Option {
+	return func(f *File) {
+		f.cacheDecoded = enabled
+	}
+}
+
+// WithIncludePadding keeps the padding fields of structures in decoded blocks, which are omitted by default.
+func WithIncludePadding() Option {
+	return func(f *File) {
+		f.includePadding = true
+	}
+}
+
+// WithOnlyCodes keeps the data of file-blocks with the given codes only, along with 'DNA1' and 'GLOB'.
+// The headers of all blocks are still read, but the data of other blocks is skipped and left nil,
+// which saves memory when only some kinds of data are of interest.
+func WithOnlyCodes(codes ...Code) Option {
+	return func(f *File) {
+		f.onlyCodes = map[Code]bool{
+			CodeDNA1:   true,
+			CodeGlobal: true,
+		}
+		for _, code := range codes {
+			f.onlyCodes[code] = true
+		}
+	}
+}
+
+// WithMetrics records the time spent parsing the file and the amount of data read, see Metrics.
+func WithMetrics() Option {
+	return func(f *File) {
+		f.metrics = &ParseMetrics{}
+	}
+}
+
+// WithMaxSDNAEntries sets the maximum number of names, types and structs the SDNA may declare each,
+// beyond which reading it fails with ErrMalformedSDNA. The default of 100000 is well above what Blender writes,
+// so the limit may be lowered to bound the memory spent on untrusted files.
+func WithMaxSDNAEntries(n int) Option {
+	return func(f *File) {
+		f.maxSDNAEntries = n
+	}
+}
+
+// WithLenientEndianness reads files whose header holds neither 'v' nor 'V' as endianness as little endian,
+// which some very old or third party files require. Such files are rejected with ErrInvalidEndianness by default.
+// If logf is not nil it is called whenever the fallback is applied.
+func WithLenientEndianness(logf func(format string, args ...interface{})) Option {
+	return func(f *File) {
+		f.lenientEndianness = true
+		f.logf = logf
+	}
+}
+
+// WithCanonicalOrder makes WriteTo write the file-blocks in the order Blender writes them,
+// with 'REND', 'TEST' and 'GLOB' first and 'DNA1' right before the final 'ENDB'.
+// Without it, blocks are written in their original order on disk.
+func WithCanonicalOrder() Option {
+	return func(f *File) {
+		f.canonicalOrder = true
+	}
+}
+
+// WithExpectedSize sets the size of the file in bytes when it is known up front, e.g. from a Content-Length header,
+// for readers like pipes whose size can not be determined otherwise. It serves as total for WithProgress,
+// and Validate reports ErrSizeMismatch if the size declared by the blocks and trailing data differs.
+func WithExpectedSize(n int64) Option {
+	return func(f *File) {
+		f.expectedSize = n
+	}
+}
+
+// WithProgress calls fn after each file-block read with the number of bytes read so far
+// and the total size given by WithExpectedSize, or 0 if the total is unknown.
+// The last call happens once all blocks and any data following the 'ENDB' block were read.
+func WithProgress(fn func(read, total int64)) Option {
+	return func(f *File) {
+		f.progress = fn
+	}
+}
@@ -0,0 +1,285 @@
+package blend
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestWithStrictValidation_blockDesync(t *testing.T) {
+	fx := newFixture(t)
+	fx.blockNamed("OB", "OBCube").Header.Size++
+
+	f, err := NewFile(bytes.NewReader(fx.bytes()), WithStrictValidation())
+	if err != nil {
+		t.Fatalf("Expected nil error, got: %v", err)
+	}
+	err = f.readFileBlocks()
+	if !errors.Is(err, ErrBlockDesync) {
+		t.Errorf("expected ErrBlockDesync, got: %v", err)
+	}
+}
+
+func TestWithStrictValidation_duplicateAddress(t *testing.T) {
+	fx := newFixture(t)
+	cube := fx.blockNamed(CodeObject, "OBCube")
+	fx.addRaw(CodeData, 0, 1, []byte{1, 2, 3, 4}).Header.OldMemoryAddress = cube.Header.OldMemoryAddress
+
+	f, err := NewFile(bytes.NewReader(fx.bytes()), WithStrictValidation())
+	if err != nil {
+		t.Fatalf("Expected nil error, got: %v", err)
+	}
+	err = f.readFileBlocks()
+	if !errors.Is(err, ErrDuplicateAddress) {
+		t.Fatalf("expected ErrDuplicateAddress, got: %v", err)
+	}
+	if msg := err.Error(); !strings.Contains(msg, "'OB'") || !strings.Contains(msg, "'DATA'") {
+		t.Errorf("expected error to name both blocks, got: %v", err)
+	}
+
+	// without strict validation the last block wins
+	b, err := fx.file().blockByAddress(cube.Header.OldMemoryAddress)
+	if err != nil {
+		t.Fatalf("Expected nil error, got: %v", err)
+	}
+	if b.Header.Code != CodeData {
+		t.Errorf("expected the last block 'DATA', got '%s'", b.Header.Code)
+	}
+}
+
+func TestWithStrictValidation_malformedEndBlock(t *testing.T) {
+	fx := newFixture(t)
+	end := fx.block(CodeEnd, 0)
+	end.Data = []byte{1, 2, 3, 4}
+	end.Header.Size = 4
+
+	f, err := NewFile(bytes.NewReader(fx.bytes()), WithStrictValidation())
+	if err != nil {
+		t.Fatalf("Expected nil error, got: %v", err)
+	}
+	if err := f.readFileBlocks(); !errors.Is(err, ErrMalformedEndBlock) {
+		t.Errorf("expected ErrMalformedEndBlock, got: %v", err)
+	}
+
+	// without strict validation the data is read along with the block
+	f, err = NewFile(bytes.NewReader(fx.bytes()))
+	if err != nil {
+		t.Fatalf("Expected nil error, got: %v", err)
+	}
+	if err := f.readFileBlocks(); err != nil {
+		t.Errorf("Expected nil error, got: %v", err)
+	}
+}
+
+func TestWithStrictValidation_pointerSizeMismatch(t *testing.T) {
+	data := newFixture(t).bytes()
+	// a 64 bit file claiming 32 bit pointers
+	data[7] = '_'
+	f, err := NewFile(bytes.NewReader(data), WithStrictValidation())
+	if err != nil {
+		t.Fatalf("Expected nil error, got: %v", err)
+	}
+	if err := f.readFileBlocks(); !errors.Is(err, ErrPointerSizeMismatch) {
+		t.Errorf("expected ErrPointerSizeMismatch, got: %v", err)
+	}
+
+	// a 32 bit file claiming 64 bit pointers
+	data = legacyFile("280", "REND", "GLOB", "OB")
+	data[7] = '-'
+	f, err = NewFile(bytes.NewReader(data), WithStrictValidation())
+	if err != nil {
+		t.Fatalf("Expected nil error, got: %v", err)
+	}
+	if err := f.readFileBlocks(); !errors.Is(err, ErrPointerSizeMismatch) {
+		t.Errorf("expected ErrPointerSizeMismatch, got: %v", err)
+	}
+}
+
+func TestWithStrictValidation_example(t *testing.T) {
+	name := "cubus-animated.blend"
+	r, err := readExample(name)
+	if err != nil {
+		t.Fatalf("Unable to read example file '%s': %s", name, err)
+	}
+	defer r.Close()
+
+	f, err := NewFile(r, WithStrictValidation())
+	if err != nil {
+		t.Fatalf("Expected nil error, got: %v", err)
+	}
+	if err := f.readFileBlocks(); err != nil {
+		t.Errorf("Expected nil error, got: %v", err)
+	}
+}
+
+func TestNewFile_invalidEndianness(t *testing.T) {
+	_, err := NewFile(bytes.NewBuffer(header('-', 'x', "280")))
+	if !errors.Is(err, ErrInvalidEndianness) {
+		t.Errorf("expected ErrInvalidEndianness, got: %v", err)
+	}
+}
+
+func TestWithLenientEndianness(t *testing.T) {
+	fx := newFixture(t)
+	fx.f.header.Endianness = 'x'
+
+	var logged []string
+	f := fx.file(WithLenientEndianness(func(format string, args ...interface{}) {
+		logged = append(logged, fmt.Sprintf(format, args...))
+	}))
+	if f.order != binary.LittleEndian {
+		t.Errorf("expected little endian byte order, got: %v", f.order)
+	}
+	if len(logged) != 1 {
+		t.Errorf("expected 1 logged message, got: %v", logged)
+	}
+	if _, err := f.Screens(); err != nil {
+		t.Errorf("Expected nil error, got: %v", err)
+	}
+}
+
+func TestWithOnlyCodes(t *testing.T) {
+	name := "cubus-animated.blend"
+	r, err := readExample(name)
+	if err != nil {
+		t.Fatalf("Unable to read example file '%s': %s", name, err)
+	}
+	defer r.Close()
+	f, err := NewFile(r, WithOnlyCodes(CodeMesh))
+	if err != nil {
+		t.Fatalf("Expected nil error, got: %v", err)
+	}
+	if err := f.loadBlocks(); err != nil {
+		t.Fatalf("Expected nil error, got: %v", err)
+	}
+	all := readExampleFile(t, name)
+
+	if len(f.blocks) != len(all.blocks) {
+		t.Fatalf("expected headers of all %d blocks, got %d", len(all.blocks), len(f.blocks))
+	}
+	for i, b := range f.blocks {
+		if b.Header != all.blocks[i].Header {
+			t.Errorf("expected header %+v at index %d, got %+v", all.blocks[i].Header, i, b.Header)
+		}
+		switch b.Header.Code {
+		case CodeMesh, CodeDNA1, CodeGlobal:
+			if !bytes.Equal(b.Data, all.blocks[i].Data) {
+				t.Errorf("expected data of block '%s' at index %d to be kept", b.Header.Code, i)
+			}
+		default:
+			if b.Data != nil {
+				t.Errorf("expected data of block '%s' at index %d to be skipped", b.Header.Code, i)
+			}
+		}
+	}
+	if _, ok := f.fileBlocks[CodeObject]; !ok {
+		t.Error("expected object blocks to be indexed")
+	}
+	if _, err := f.SDNA(); err != nil {
+		t.Errorf("Expected nil error, got: %v", err)
+	}
+}
+
+func TestWithOnlyCodes_truncated(t *testing.T) {
+	fx := newFixture(t)
+	data := fx.bytes()
+	offset, err := fx.f.BlockOffset(CodeObject)
+	if err != nil {
+		t.Fatalf("Expected nil error, got: %v", err)
+	}
+
+	f, err := NewFile(bytes.NewReader(data[:offset+30]), WithOnlyCodes(CodeMesh))
+	if err != nil {
+		t.Fatalf("Expected nil error, got: %v", err)
+	}
+	if err := f.loadBlocks(); !errors.Is(err, io.ErrUnexpectedEOF) {
+		t.Errorf("expected io.ErrUnexpectedEOF, got: %v", err)
+	}
+}
+
+func TestWithMaxSDNAEntries(t *testing.T) {
+	data := newFixture(t).bytes()
+
+	f, err := NewFile(bytes.NewReader(data), WithMaxSDNAEntries(100))
+	if err != nil {
+		t.Fatalf("Expected nil error, got: %v", err)
+	}
+	if _, err := f.SDNA(); !errors.Is(err, ErrMalformedSDNA) {
+		t.Errorf("expected ErrMalformedSDNA, got: %v", err)
+	}
+
+	f, err = NewFile(bytes.NewReader(data), WithMaxSDNAEntries(1<<20))
+	if err != nil {
+		t.Fatalf("Expected nil error, got: %v", err)
+	}
+	if _, err := f.SDNA(); err != nil {
+		t.Errorf("Expected nil error, got: %v", err)
+	}
+}
+
+func TestWithExpectedSize(t *testing.T) {
+	data, err := ioutil.ReadFile(filepath.Join("./examples", "cubus-animated.blend"))
+	if err != nil {
+		t.Fatalf("Unable to read example file: %v", err)
+	}
+
+	// a pipe hides the size of the file from the reader
+	var last, total int64
+	calls := 0
+	f, err := NewFile(ioutil.NopCloser(bytes.NewReader(data)), WithExpectedSize(int64(len(data))), WithProgress(func(read, size int64) {
+		if read < last {
+			t.Errorf("expected progress to increase, got %d after %d", read, last)
+		}
+		last, total = read, size
+		calls++
+	}))
+	if err != nil {
+		t.Fatalf("Expected nil error, got: %v", err)
+	}
+	blocks, err := f.ReadAllBlocks()
+	if err != nil {
+		t.Fatalf("Expected nil error, got: %v", err)
+	}
+	if calls != len(blocks) {
+		t.Errorf("expected progress after each of %d blocks, got %d calls", len(blocks), calls)
+	}
+	if last != total || total != int64(len(data)) {
+		t.Errorf("expected progress to reach %d bytes, got %d of %d", len(data), last, total)
+	}
+	if err := f.Validate(); err != nil {
+		t.Errorf("Expected nil error, got: %v", err)
+	}
+
+	f, err = NewFile(bytes.NewReader(data), WithExpectedSize(int64(len(data))+1))
+	if err != nil {
+		t.Fatalf("Expected nil error, got: %v", err)
+	}
+	if err := f.Validate(); !errors.Is(err, ErrSizeMismatch) {
+		t.Errorf("expected ErrSizeMismatch, got: %v", err)
+	}
+}
+
+func TestPlausibleBlockCode(t *testing.T) {
+	testTable := map[string]bool{
+		"OB\x00\x00":       true,
+		"DNA1":             true,
+		"REND":             true,
+		"\x00\x00\x00\x00": false,
+		"B\x00\x00\x88":    false,
+		"ob\x00\x00":       false,
+		"O\x00B\x00":       false,
+	}
+	for code, expected := range testTable {
+		var c [4]byte
+		copy(c[:], code)
+		if plausible := plausibleBlockCode(c); plausible != expected {
+			t.Errorf("expected %q to be plausible: %v, got %v", code, expected, plausible)
+		}
+	}
+}
@@ -0,0 +1,82 @@
+package blend
+
+import "fmt"
+
+// ParticleSystem is a particle system of an object along with its settings.
+type ParticleSystem struct {
+	// Name of the particle system
+	Name string
+	// Name of the particle settings datablock without its ID code, empty if the system has none
+	Settings string
+	// Type of the particles as used by the Python API, e.g. EMITTER or HAIR
+	Type string
+	// Number of particles emitted
+	Count int
+}
+
+// particleTypeNames maps the values of a ParticleSettings' `type` field to the names used by the Python API.
+var particleTypeNames = map[int]string{
+	0: "EMITTER",
+	1: "REACTOR",
+	2: "HAIR",
+	3: "FLUID",
+}
+
+// ParticleTypeName returns the name of a ParticleSettings' `type`, e.g. HAIR, or "UNKNOWN".
+func ParticleTypeName(t int) string {
+	if name, ok := particleTypeNames[t]; ok {
+		return name
+	}
+	return "UNKNOWN"
+}
+
+// ParticleSystems decodes the particle systems of the object located at objectAddr.
+// Objects without particle systems return an empty slice.
+func (f *File) ParticleSystems(objectAddr uint64) ([]ParticleSystem, error) {
+	ob, err := f.structAt(objectAddr, "Object")
+	if err != nil {
+		return nil, err
+	}
+	first, err := ob.pointer("particlesystem.first")
+	if err != nil {
+		return nil, err
+	}
+
+	systems := []ParticleSystem{}
+	err = f.walkList(first, func(psys *instance) error {
+		name, err := psys.string("name")
+		if err != nil {
+			return err
+		}
+		system := ParticleSystem{Name: name}
+		part, err := psys.pointer("part")
+		if err != nil {
+			return err
+		}
+		if part != 0 {
+			settings, err := f.structAt(part, "ParticleSettings")
+			if err != nil {
+				return err
+			}
+			if system.Settings, err = settings.idName(); err != nil {
+				return err
+			}
+			t, err := settings.int("type")
+			if err != nil {
+				return err
+			}
+			system.Type = ParticleTypeName(int(t))
+			count, err := settings.int("totpart")
+			if err != nil {
+				return err
+			}
+			system.Count = int(count)
+		}
+		systems = append(systems, system)
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("blend: unable to read particle systems of object at %#x: %w", objectAddr, err)
+	}
+	return systems, nil
+}
@@ -0,0 +1,63 @@
+package blend
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParticleTypeName(t *testing.T) {
+	testTable := map[int]string{
+		0: "EMITTER",
+		2: "HAIR",
+		3: "FLUID",
+		4: "UNKNOWN",
+	}
+	for typ, expected := range testTable {
+		if name := ParticleTypeName(typ); name != expected {
+			t.Errorf("expected %q for type %d, got %q", expected, typ, name)
+		}
+	}
+}
+
+func TestFile_ParticleSystems(t *testing.T) {
+	fx := newFixture(t)
+	ob := fx.blockNamed("OB", "OBCube")
+	settings := fx.add(CodeParticleSettings, "ParticleSettings", 1)
+	fx.set(settings, 0, "id.name", "PAFur")
+	fx.set(settings, 0, "type", 2)
+	fx.set(settings, 0, "totpart", 1000)
+	fur := fx.add("DATA", "ParticleSystem", 1)
+	fx.set(fur, 0, "name", "Fur")
+	fx.set(fur, 0, "part", settings.Header.OldMemoryAddress)
+	empty := fx.add("DATA", "ParticleSystem", 1)
+	fx.set(empty, 0, "name", "Empty")
+	fx.set(fur, 0, "next", empty.Header.OldMemoryAddress)
+	fx.set(ob, 0, "particlesystem.first", fur.Header.OldMemoryAddress)
+	fx.set(ob, 0, "particlesystem.last", empty.Header.OldMemoryAddress)
+
+	systems, err := fx.file().ParticleSystems(ob.Header.OldMemoryAddress)
+	if err != nil {
+		t.Fatalf("Expected nil error, got: %v", err)
+	}
+	expected := []ParticleSystem{
+		{Name: "Fur", Settings: "Fur", Type: "HAIR", Count: 1000},
+		{Name: "Empty"},
+	}
+	if !reflect.DeepEqual(systems, expected) {
+		t.Errorf("expected %+v, got: %+v", expected, systems)
+	}
+}
+
+func TestFile_ParticleSystemsNone(t *testing.T) {
+	f := readExampleFile(t, "cubus-animated.blend")
+
+	for _, b := range f.fileBlocks[CodeObject] {
+		systems, err := f.ParticleSystems(b.Header.OldMemoryAddress)
+		if err != nil {
+			t.Fatalf("Expected nil error, got: %v", err)
+		}
+		if systems == nil || len(systems) != 0 {
+			t.Errorf("expected no particle systems, got: %v", systems)
+		}
+	}
+}
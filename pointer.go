@@ -0,0 +1,67 @@
+package blend
+
+import "fmt"
+
+// ResolvePointerArray reads count pointers from the file-block located at addr,
+// which is how Blender stores the targets of fields like the `**mat` materials of a mesh.
+// Null entries are returned as 0.
+func (f *File) ResolvePointerArray(addr uint64, count int) ([]uint64, error) {
+	if count < 0 {
+		return nil, fmt.Errorf("blend: negative pointer count %d", count)
+	}
+	if count == 0 {
+		return []uint64{}, nil
+	}
+	if err := f.loadBlocks(); err != nil {
+		return nil, err
+	}
+	b, err := f.blockByAddress(addr)
+	if err != nil {
+		return nil, err
+	}
+	size := f.PointerSize()
+	data, err := safeSlice(b.Data, 0, count*size)
+	if err != nil {
+		return nil, fmt.Errorf("blend: unable to read %d pointers at %#x: %w", count, addr, err)
+	}
+	pointers := make([]uint64, count)
+	for i := range pointers {
+		if pointers[i], err = f.readPointer(data, i*size); err != nil {
+			return nil, err
+		}
+	}
+	return pointers, nil
+}
+
+// readPointer reads a pointer of the file's pointer size and byte order at offset of data, widened to 64 bits.
+func (f *File) readPointer(data []byte, offset int) (uint64, error) {
+	size := f.PointerSize()
+	b, err := safeSlice(data, offset, size)
+	if err != nil {
+		return 0, fmt.Errorf("blend: unable to read pointer: %w", err)
+	}
+	if size == 4 {
+		return uint64(f.order.Uint32(b)), nil
+	}
+	return f.order.Uint64(b), nil
+}
+
+// FollowPointer reads the pointer field fieldName of the structure located at fromAddr and decodes the
+// structure it points to, which must be of type expectedType. The field name may be a path like "id.next".
+// Only the first structure of the block pointed to is decoded, into a new map which is not cached.
+// A null pointer returns a nil map.
+func (f *File) FollowPointer(fromAddr uint64, fieldName, expectedType string) (map[string]interface{}, error) {
+	in, err := f.instanceAt(fromAddr)
+	if err != nil {
+		return nil, err
+	}
+	addr, err := in.pointer(fieldName)
+	if err != nil || addr == 0 {
+		return nil, err
+	}
+	target, err := f.structAt(addr, expectedType)
+	if err != nil {
+		return nil, fmt.Errorf("blend: unable to follow field '%s' of %s at %#x: %w", fieldName, in.typeName(), fromAddr, err)
+	}
+	return target.decode(0)
+}
@@ -0,0 +1,126 @@
+package blend
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestFile_ResolvePointerArray(t *testing.T) {
+	f := readExampleFile(t, "cubus-animated.blend")
+	if _, err := f.SDNA(); err != nil {
+		t.Fatalf("Expected nil error, got: %v", err)
+	}
+	var me *instance
+	for _, b := range f.fileBlocks[CodeMesh] {
+		in, err := f.blockInstance(b, 0)
+		if err != nil {
+			t.Fatalf("Expected nil error, got: %v", err)
+		}
+		if name, _ := in.idName(); name == "Cube" {
+			me = in
+		}
+	}
+	if me == nil {
+		t.Fatal("expected mesh 'Cube' in the example")
+	}
+	mat, err := me.pointer("mat")
+	if err != nil {
+		t.Fatalf("Expected nil error, got: %v", err)
+	}
+	count, err := me.int("totcol")
+	if err != nil {
+		t.Fatalf("Expected nil error, got: %v", err)
+	}
+
+	pointers, err := f.ResolvePointerArray(mat, int(count))
+	if err != nil {
+		t.Fatalf("Expected nil error, got: %v", err)
+	}
+	if len(pointers) != 1 {
+		t.Fatalf("expected 1 material, got %d", len(pointers))
+	}
+	ma, err := f.structAt(pointers[0], "Material")
+	if err != nil {
+		t.Fatalf("Expected nil error, got: %v", err)
+	}
+	if name, _ := ma.idName(); name != "Material" {
+		t.Errorf("expected material 'Material', got '%s'", name)
+	}
+
+	if _, err := f.ResolvePointerArray(mat, int(count)+1); !errors.Is(err, ErrShortBlockData) {
+		t.Errorf("expected ErrShortBlockData, got: %v", err)
+	}
+	if pointers, err := f.ResolvePointerArray(0, 0); err != nil || len(pointers) != 0 {
+		t.Errorf("expected no pointers, got: %v, %v", pointers, err)
+	}
+}
+
+func TestFile_readPointer(t *testing.T) {
+	f := readExampleFile(t, "cubus-animated.blend")
+	raw, err := f.FieldBytes(CodeObject, 0, "data")
+	if err != nil {
+		t.Fatalf("Expected nil error, got: %v", err)
+	}
+	addr, err := f.readPointer(raw, 0)
+	if err != nil {
+		t.Fatalf("Expected nil error, got: %v", err)
+	}
+	if camera := f.fileBlocks[CodeCamera][0].Header.OldMemoryAddress; addr != camera {
+		t.Errorf("expected pointer %#x to the camera, got %#x", camera, addr)
+	}
+	if _, err := f.readPointer(raw, 1); !errors.Is(err, ErrShortBlockData) {
+		t.Errorf("expected ErrShortBlockData reading past the data, got: %v", err)
+	}
+
+	f, err = NewFile(bytes.NewBuffer(header('_', 'V', "280")))
+	if err != nil {
+		t.Fatalf("Expected nil error, got: %v", err)
+	}
+	addr, err = f.readPointer([]byte{0xff, 0x12, 0x34, 0x56, 0x78, 0xff}, 1)
+	if err != nil {
+		t.Fatalf("Expected nil error, got: %v", err)
+	}
+	if addr != 0x12345678 {
+		t.Errorf("expected 4 byte big endian pointer 0x12345678, got %#x", addr)
+	}
+}
+
+func TestFile_FollowPointer(t *testing.T) {
+	fx := newFixture(t)
+	cube := fx.blockNamed("OB", "OBCube")
+	camera := fx.blockNamed("OB", "OBCamera").Header.OldMemoryAddress
+	f := fx.f
+
+	me, err := f.FollowPointer(cube.Header.OldMemoryAddress, "data", "Mesh")
+	if err != nil {
+		t.Fatalf("Expected nil error, got: %v", err)
+	}
+	if id, ok := me["id"].(map[string]interface{}); !ok || id["name"] != "MECube" {
+		t.Errorf("expected mesh 'MECube', got: %v", me["id"])
+	}
+	if _, err := f.FollowPointer(camera, "data", "Mesh"); err == nil || !strings.Contains(err.Error(), "expected Mesh") {
+		t.Errorf("expected error following the data of a camera as Mesh, got: %v", err)
+	}
+	if _, err := f.FollowPointer(camera, "id.name", "Mesh"); err == nil {
+		t.Error("expected error following a field which is no pointer")
+	}
+	parent, err := f.FollowPointer(camera, "parent", "Object")
+	if err != nil || parent != nil {
+		t.Errorf("expected nil map for a null pointer, got %v: %v", parent, err)
+	}
+}
+
+func TestFile_FollowPointerEmptyBlock(t *testing.T) {
+	fx := newFixture(t)
+	cube := fx.blockNamed(CodeObject, "OBCube")
+	me := fx.block(CodeMesh, 0)
+	me.Header.Count = 0
+	me.Data = nil
+	me.Header.Size = 0
+
+	if _, err := fx.file().FollowPointer(cube.Header.OldMemoryAddress, "data", "Mesh"); err == nil {
+		t.Error("expected error following a pointer to a block holding no structure")
+	}
+}
@@ -0,0 +1,83 @@
+package blend
+
+import (
+	"fmt"
+	"image"
+)
+
+// Preview returns the preview image of the datablock located at addr, like the thumbnail of a material
+// shown by the asset browser. Of the icon and the larger preview Blender may store, the larger one is returned.
+// ErrNoPreview is returned if the datablock has no preview.
+func (f *File) Preview(addr uint64) (image.Image, error) {
+	in, err := f.instanceAt(addr)
+	if err != nil {
+		return nil, err
+	}
+	if !in.hasField("preview") {
+		return nil, fmt.Errorf("%w: %s at %#x", ErrNoPreview, in.typeName(), addr)
+	}
+	preview, err := in.pointer("preview")
+	if err != nil {
+		return nil, err
+	}
+	if preview == 0 {
+		return nil, fmt.Errorf("%w: %s at %#x", ErrNoPreview, in.typeName(), addr)
+	}
+	prv, err := f.structAt(preview, "PreviewImage")
+	if err != nil {
+		return nil, err
+	}
+	_, widths, err := prv.field("w")
+	if err != nil {
+		return nil, err
+	}
+	_, heights, err := prv.field("h")
+	if err != nil {
+		return nil, err
+	}
+	_, rects, err := prv.field("rect")
+	if err != nil {
+		return nil, err
+	}
+
+	// the sizes are ordered from the icon to the preview
+	for size := len(widths)/4 - 1; size >= 0; size-- {
+		w, err := f.decodeInt("int", widths[4*size:])
+		if err != nil {
+			return nil, err
+		}
+		h, err := f.decodeInt("int", heights[4*size:])
+		if err != nil {
+			return nil, err
+		}
+		rect, err := f.readPointer(rects, size*f.PointerSize())
+		if err != nil {
+			return nil, err
+		}
+		if w <= 0 || h <= 0 || rect == 0 {
+			continue
+		}
+		return f.previewImage(rect, int(w), int(h))
+	}
+	return nil, fmt.Errorf("%w: preview of %s at %#x holds no image", ErrNoPreview, in.typeName(), addr)
+}
+
+// previewImage decodes the w by h pixels of 4 bytes each located at addr.
+// The rows are stored from the bottom to the top.
+func (f *File) previewImage(addr uint64, w, h int) (image.Image, error) {
+	b, err := f.blockByAddress(addr)
+	if err != nil {
+		return nil, err
+	}
+	// divide rather than multiply, as the size of huge previews overflows
+	if w > len(b.Data)/4/h {
+		return nil, fmt.Errorf("%w: %dx%d preview exceeds data length %d of block '%s' at %#x",
+			ErrShortBlockData, w, h, len(b.Data), b.Header.Code, addr)
+	}
+	stride := 4 * w
+	img := image.NewNRGBA(image.Rect(0, 0, w, h))
+	for y := 0; y < h; y++ {
+		copy(img.Pix[y*img.Stride:], b.Data[(h-1-y)*stride:(h-y)*stride])
+	}
+	return img, nil
+}
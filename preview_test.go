@@ -0,0 +1,97 @@
+package blend
+
+import (
+	"errors"
+	"image/color"
+	"testing"
+)
+
+func TestFile_Preview(t *testing.T) {
+	f := readExampleFile(t, "cubus-animated.blend")
+
+	img, err := f.Preview(f.fileBlocks[CodeMaterial][0].Header.OldMemoryAddress)
+	if err != nil {
+		t.Fatalf("Expected nil error, got: %v", err)
+	}
+	if b := img.Bounds(); b.Dx() != 32 || b.Dy() != 32 {
+		t.Errorf("expected 32x32 icon of the material, got: %v", b)
+	}
+	if _, _, _, a := img.At(16, 16).RGBA(); a == 0 {
+		t.Error("expected opaque center of the material icon")
+	}
+
+	for _, code := range []Code{CodeObject, CodeWorld} {
+		if _, err := f.Preview(f.fileBlocks[code][0].Header.OldMemoryAddress); !errors.Is(err, ErrNoPreview) {
+			t.Errorf("expected ErrNoPreview for block '%s', got: %v", code, err)
+		}
+	}
+}
+
+// addPreview adds a preview of an icon and a larger image of the given size holding pixels to the material ma.
+func addPreview(fx *fixture, ma *Block, w, h uint64, pixels []byte) {
+	fx.t.Helper()
+	icon := fx.addRaw(CodeData, 0, 1, []byte{0, 0, 255, 255})
+	rect := fx.addRaw(CodeData, 0, 1, pixels)
+	prv := fx.add(CodeData, "PreviewImage", 1)
+	in, err := fx.f.blockInstance(prv, 0)
+	if err != nil {
+		fx.t.Fatalf("Expected nil error, got: %v", err)
+	}
+	order := fx.f.order
+	for _, field := range []struct {
+		name  string
+		sizes [2]uint64
+	}{
+		{"w", [2]uint64{1, w}},
+		{"h", [2]uint64{1, h}},
+		{"rect", [2]uint64{icon.Header.OldMemoryAddress, rect.Header.OldMemoryAddress}},
+	} {
+		l, data, err := in.field(field.name)
+		if err != nil {
+			fx.t.Fatalf("Expected nil error, got: %v", err)
+		}
+		for i, v := range field.sizes {
+			if l.pointerDepth > 0 {
+				order.PutUint64(data[8*i:], v)
+			} else {
+				order.PutUint32(data[4*i:], uint32(v))
+			}
+		}
+	}
+	fx.set(ma, 0, "preview", prv.Header.OldMemoryAddress)
+}
+
+func TestFile_PreviewLarge(t *testing.T) {
+	fx := newFixture(t)
+	ma := fx.blockNamed("MA", "MAMaterial")
+	// two rows of two pixels, stored from the bottom to the top
+	addPreview(fx, ma, 2, 2, []byte{
+		255, 0, 0, 255, 255, 0, 0, 255,
+		0, 255, 0, 128, 0, 255, 0, 128,
+	})
+
+	img, err := fx.file().Preview(ma.Header.OldMemoryAddress)
+	if err != nil {
+		t.Fatalf("Expected nil error, got: %v", err)
+	}
+	if b := img.Bounds(); b.Dx() != 2 || b.Dy() != 2 {
+		t.Fatalf("expected the 2x2 preview rather than the icon, got: %v", b)
+	}
+	if c := img.At(0, 0); c != (color.NRGBA{G: 255, A: 128}) {
+		t.Errorf("expected the last stored row at the top, got: %v", c)
+	}
+	if c := img.At(1, 1); c != (color.NRGBA{R: 255, A: 255}) {
+		t.Errorf("expected the first stored row at the bottom, got: %v", c)
+	}
+}
+
+func TestFile_PreviewHuge(t *testing.T) {
+	fx := newFixture(t)
+	ma := fx.blockNamed("MA", "MAMaterial")
+	// the number of bytes of this size overflows 64 bits when computed naively
+	addPreview(fx, ma, 0x7fffffff, 0x7fffffff, make([]byte, 16))
+
+	if _, err := fx.file().Preview(ma.Header.OldMemoryAddress); !errors.Is(err, ErrShortBlockData) {
+		t.Errorf("expected ErrShortBlockData, got: %v", err)
+	}
+}
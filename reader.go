@@ -6,172 +6,821 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"io/ioutil"
+	"reflect"
 	"strings"
+	"sync"
+	"time"
 )
 
 type File struct {
-	r            io.Reader
-	header       *FileHeader
-	order        binary.ByteOrder
-	pointerSize  uint8
-	fileBlocks64 map[string]FileBlock64
-	fileBlocks32 map[string]FileBlock32
+	r           io.Reader
+	header      *FileHeader
+	order       binary.ByteOrder
+	pointerSize uint8
+	blocks      []*Block
+	fileBlocks  map[Code][]*Block
+	addresses   map[uint64]*Block
+	blocksRead  bool
+	trailing    []byte
+	sdna        *StructureDNA
+	strict      bool
+	lastHeader  *BlockHeader
+	counter     *countingReader
+	rewind      func() (io.Reader, error)
+	released    bool
+
+	closers []io.Closer
+
+	lenientEndianness bool
+	logf              func(format string, args ...interface{})
+
+	cacheDecoded   bool
+	decoded        map[uint64]decodeEntry
+	includePadding bool
+
+	onlyCodes      map[Code]bool
+	metrics        *ParseMetrics
+	maxSDNAEntries int
+	canonicalOrder bool
+	expectedSize   int64
+	progress       func(read, total int64)
+
+	// number of block headers read and the last bytes of the first block, see pointerSizeMismatch
+	headersRead int
+	firstTail   []byte
 }
 
 // NewFile initializes the File struct and reads the header.
 // This automatically determines the byte order, after which the rest of the file can be read if needed.
-func NewFile(r io.Reader) (*File, error) {
+func NewFile(r io.Reader, opts ...Option) (*File, error) {
+	counter := &countingReader{r: r}
 	f := File{
-		r: r,
+		r:              counter,
+		counter:        counter,
+		cacheDecoded:   true,
+		rewind:         seekerRewind(r),
+		maxSDNAEntries: defaultMaxSDNAEntries,
+	}
+	for _, opt := range opts {
+		opt(&f)
+	}
+	var start time.Time
+	if f.metrics != nil {
+		start = time.Now()
 	}
 	if err := f.readHeader(); err != nil {
 		return nil, err
 	}
-	if f.pointerSize == 64 {
-		f.fileBlocks64 = make(map[string]FileBlock64)
-	} else {
-		f.fileBlocks32 = make(map[string]FileBlock32)
+	if f.metrics != nil {
+		track(&f.metrics.Header, start)
+		f.metrics.BytesRead += f.offset()
 	}
+	f.fileBlocks = make(map[Code][]*Block)
+	f.addresses = make(map[uint64]*Block)
 
 	return &f, nil
 }
 
+// ReadHeader reads only the 12 byte header of a blend file from r, returning it along with the byte order
+// and the pointer size in bits. It is meant for scanning many files, e.g. for their version, as it reads
+// nothing else and allocates less than NewFile.
+func ReadHeader(r io.Reader) (FileHeader, Endianness, int, error) {
+	var data [12]byte
+	if err := readHeaderBytes(r, data[:]); err != nil {
+		return FileHeader{}, 0, 0, err
+	}
+	var e Endianness
+	switch data[8] {
+	case 'v':
+		e = Little
+	case 'V':
+		e = Big
+	default:
+		return FileHeader{}, 0, 0, fmt.Errorf("%w: %q", ErrInvalidEndianness, data[8])
+	}
+	header, err := decodeFileHeader(data[:])
+	if err != nil {
+		return FileHeader{}, 0, 0, err
+	}
+	return header, e, headerPointerSize(header), nil
+}
+
 // readHeader reads the first 12 bytes which represent a blender file header.
 // most importantly the byte order is determined upon which the rest of the file can be read successfully.
 func (f *File) readHeader() error {
-	header := FileHeader{}
-	data, err := readNextBytes(f.r, 12)
-	if err != nil {
-		return err
+	data := make([]byte, 12)
+	if err := readHeaderBytes(f.r, data); err != nil {
+		if errors.Is(err, ErrShortHeader) {
+			return &OpenError{Kind: KindFormat, Err: err}
+		}
+		return &OpenError{Kind: KindIO, Err: err}
 	}
-	buffer := bytes.NewBuffer(data)
 
 	// determine byte order before trying to parse
 	// byte order is within the file header at offset 8, c type `char`
-	var order binary.ByteOrder = binary.LittleEndian
-	if data[8] == 'V' {
+	var order binary.ByteOrder
+	switch data[8] {
+	case 'v':
+		order = binary.LittleEndian
+	case 'V':
 		order = binary.BigEndian
+	default:
+		if !f.lenientEndianness {
+			return &OpenError{Kind: KindFormat, Err: fmt.Errorf("%w: %q", ErrInvalidEndianness, data[8])}
+		}
+		if f.logf != nil {
+			f.logf("blend: unknown endianness %q, assuming little endian", data[8])
+		}
+		order = binary.LittleEndian
+	}
+	header, err := decodeFileHeader(data)
+	if err != nil {
+		return &OpenError{Kind: KindFormat, Err: err}
 	}
-	if err = binary.Read(buffer, order, &header); err != nil {
+	if f.strict && !isDigits(header.Version[:]) {
+		return &OpenError{Kind: KindValidation, Err: fmt.Errorf("%w: %q", ErrInvalidVersion, header.Version[:])}
+	}
+
+	f.pointerSize = uint8(headerPointerSize(header))
+	f.order = order
+	f.header = &header
+	return nil
+}
+
+// readHeaderBytes reads the 12 bytes of the file header into data.
+func readHeaderBytes(r io.Reader, data []byte) error {
+	// a partial read would misplace every field, so the header is read in full
+	if _, err := io.ReadFull(r, data); err != nil {
+		// empty input is no blend file either, rather than a failure to read
+		if errors.Is(err, io.ErrUnexpectedEOF) || errors.Is(err, io.EOF) {
+			return ErrShortHeader
+		}
 		return err
 	}
-	identifier := string(header.Identifier[:])
-	if identifier != "BLENDER" {
-		return errors.New("blend: invalid identifier")
+	return nil
+}
+
+// decodeFileHeader decodes the 12 bytes of a file header and checks its identifier.
+// The header consists of characters only, hence it does not depend on the byte order.
+func decodeFileHeader(data []byte) (FileHeader, error) {
+	var header FileHeader
+	copy(header.Identifier[:], data[0:7])
+	header.PointerSize = data[7]
+	header.Endianness = data[8]
+	copy(header.Version[:], data[9:12])
+	if string(header.Identifier[:]) != "BLENDER" {
+		return FileHeader{}, ErrInvalidIdentifier
 	}
+	return header, nil
+}
 
-	f.pointerSize = 64
+// isDigits reports whether b consists of ASCII digits only.
+func isDigits(b []byte) bool {
+	for _, c := range b {
+		if c < '0' || c > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// headerPointerSize returns the pointer size in bits indicated by a file header.
+func headerPointerSize(header FileHeader) int {
 	if header.PointerSize == '_' {
-		f.pointerSize = 32
+		return 32
 	}
+	return 64
+}
 
-	f.order = order
-	f.header = &header
-	return nil
+// Version returns the version of Blender the file was saved with, e.g. 2 and 80 for Blender 2.80.
+func (f *File) Version() (major, minor int) {
+	v := f.header.Version
+	return int(v[0] - '0'), int(v[1]-'0')*10 + int(v[2]-'0')
+}
+
+// Endianness returns the byte order the file was written in.
+func (f *File) Endianness() Endianness {
+	if f.order == binary.BigEndian {
+		return Big
+	}
+	return Little
+}
+
+// PointerSize returns the size of pointers of the file in bytes, which is either 4 or 8.
+func (f *File) PointerSize() int {
+	return int(f.pointerSize) / 8
 }
 
 // readFileBlocks reads all file blocks and builds up the cache structure.
 func (f *File) readFileBlocks() error {
+	if f.metrics != nil {
+		defer track(&f.metrics.Blocks, time.Now())
+	}
 	for {
-		if f.pointerSize == 64 {
-			header, err := f.readFileBlockHeader64()
-			if err != nil {
-				if errors.Is(err, io.EOF) {
-					return nil
-				}
-				return err
-			}
-			data, err := readNextBytes(f.r, int(header.Size))
-			if err != nil {
-				return err
+		b, err := f.readBlock()
+		if err != nil {
+			if errors.Is(err, io.EOF) {
+				f.blocksRead = true
+				return nil
 			}
+			return err
+		}
+
+		// under strict validation a reused address reveals corruption, otherwise the last block wins
+		if prev, ok := f.addresses[b.Header.OldMemoryAddress]; ok && f.strict && addressed(prev) && addressed(b) {
+			return fmt.Errorf("%w: blocks '%s' and '%s' at %#x", ErrDuplicateAddress, prev.Header.Code, b.Header.Code, b.Header.OldMemoryAddress)
+		}
+		f.blocks = append(f.blocks, b)
+		f.fileBlocks[b.Header.Code] = append(f.fileBlocks[b.Header.Code], b)
+		if b.Header.OldMemoryAddress != 0 {
+			f.addresses[b.Header.OldMemoryAddress] = b
+		}
+		f.reportProgress()
 
-			f.fileBlocks64[byteSliceToString(header.Code[:])] = FileBlock64{
-				header: header,
-				data:   data,
+		// anything following the last block was appended by other tools
+		if b.Header.Code == CodeEnd {
+			if f.strict && b.Header.Size != 0 {
+				return fmt.Errorf("%w: declares %d bytes of data", ErrMalformedEndBlock, b.Header.Size)
 			}
-		} else {
-			header, err := f.readFileBlockHeader32()
+			trailing, err := ioutil.ReadAll(f.r)
 			if err != nil {
-				return err
+				return fmt.Errorf("blend: unable to read data after ENDB block: %w", err)
 			}
-			data, err := readNextBytes(f.r, int(header.Size))
-			if err != nil {
-				return err
+			if len(trailing) > 0 {
+				f.trailing = trailing
+				f.reportProgress()
 			}
+			f.blocksRead = true
+			return nil
+		}
+	}
+}
 
-			f.fileBlocks32[byteSliceToString(header.Code[:])] = FileBlock32{
-				header: header,
-				data:   data,
-			}
+// reportProgress passes the number of bytes read so far to the callback of WithProgress, if any.
+func (f *File) reportProgress() {
+	if f.progress != nil {
+		f.progress(f.offset(), f.expectedSize)
+	}
+}
+
+// addressed reports whether the address of b is unique within a well-formed file.
+// Blender writes 'REND', 'TEST' and 'GLOB' from temporary memory, so they may share an address,
+// and nothing points to 'DNA1' and 'ENDB'.
+func addressed(b *Block) bool {
+	switch b.Header.Code {
+	case CodeRender, CodeTest, CodeGlobal, CodeDNA1, CodeEnd:
+		return false
+	}
+	return b.Header.OldMemoryAddress != 0
+}
+
+// TrailingData returns the bytes following the 'ENDB' block once all blocks were read, or nil if there are none.
+func (f *File) TrailingData() []byte {
+	return f.trailing
+}
+
+// readBlock reads the next file-block header and its data.
+// Block data is never compressed on its own, Blender only compresses whole files, which Open takes care of.
+func (f *File) readBlock() (*Block, error) {
+	offset := f.offset()
+	header, err := f.readBlockHeader()
+	if err != nil {
+		return nil, err
+	}
+	f.lastHeader = header
+	f.headersRead++
+	if f.onlyCodes != nil && !f.onlyCodes[header.Code] {
+		if err := f.skip(int64(header.Size)); err != nil {
+			return nil, fmt.Errorf("blend: unable to skip data of block '%s' at %#x: %w", header.Code, header.OldMemoryAddress, err)
+		}
+		f.countBlock(offset)
+		return &Block{
+			Header: *header,
+			offset: offset,
+		}, nil
+	}
+	data, err := f.readData(int64(header.Size))
+	if err != nil {
+		return nil, fmt.Errorf("blend: unable to read data of block '%s' at %#x: %w", header.Code, header.OldMemoryAddress, err)
+	}
+	if f.headersRead == 1 && len(data) >= 4 {
+		f.firstTail = data[len(data)-4:]
+	}
+	f.countBlock(offset)
+	return &Block{
+		Header: *header,
+		Data:   data,
+		offset: offset,
+	}, nil
+}
+
+// ReadAllBlocks reads all file-blocks unless that already happened and returns them in the order of the file.
+// The blocks are shared with the File, so their data must not be modified, see BlockDataCopy.
+func (f *File) ReadAllBlocks() ([]*Block, error) {
+	if err := f.loadBlocks(); err != nil {
+		return nil, err
+	}
+	return f.blocks, nil
+}
+
+// BlockCount returns the number of file-blocks with the given code once the blocks were read, e.g. by ReadAllBlocks.
+// This is not to be confused with the number of structures stored within a single block.
+func (f *File) BlockCount(code Code) int {
+	return len(f.fileBlocks[code])
+}
+
+// DeclaredSize returns the size of the file as declared by its blocks, which is the size of the file header
+// plus the header and data size of every block. A file of a different size is truncated or has trailing data.
+func (f *File) DeclaredSize() (int64, error) {
+	blocks, err := f.ReadAllBlocks()
+	if err != nil {
+		return 0, err
+	}
+	size := int64(12)
+	for _, b := range blocks {
+		size += f.blockHeaderSize() + int64(b.Header.Size)
+	}
+	return size, nil
+}
+
+// blockHeaderSize returns the size of a file-block header, which depends on the pointer size.
+func (f *File) blockHeaderSize() int64 {
+	if f.pointerSize == 32 {
+		return 20
+	}
+	return 24
+}
+
+// loadBlocks reads all file blocks unless that already happened.
+func (f *File) loadBlocks() error {
+	if f.blocksRead {
+		return nil
+	}
+	if f.released {
+		return ErrReleased
+	}
+	return f.readFileBlocks()
+}
+
+// readBlockHeader reads the next file-block header according to the pointer size of the file.
+func (f *File) readBlockHeader() (*BlockHeader, error) {
+	if f.pointerSize == 64 {
+		h, err := f.readFileBlockHeader64()
+		if err != nil {
+			return nil, err
+		}
+		code, err := f.blockCode(h.Code)
+		if err != nil {
+			// with 4 byte pointers, the header would have started at the last 4 bytes read as data
+			return nil, f.pointerSizeMismatch(err, f.firstTail)
+		}
+		return &BlockHeader{
+			Code:             code,
+			Size:             h.Size,
+			OldMemoryAddress: h.OldMemoryAddress,
+			SDNAIndex:        h.SDNAIndex,
+			Count:            h.Count,
+		}, nil
+	}
+	h, err := f.readFileBlockHeader32()
+	if err != nil {
+		return nil, err
+	}
+	code, err := f.blockCode(h.Code)
+	if err != nil {
+		// with 8 byte pointers, the header would start 4 bytes later, where the size was read from
+		alt := make([]byte, 4)
+		f.order.PutUint32(alt, h.Size)
+		return nil, f.pointerSizeMismatch(err, alt)
+	}
+	return &BlockHeader{
+		Code:             code,
+		Size:             h.Size,
+		OldMemoryAddress: uint64(h.OldMemoryAddress),
+		SDNAIndex:        h.SDNAIndex,
+		Count:            h.Count,
+	}, nil
+}
+
+// pointerSizeMismatch turns err into ErrPointerSizeMismatch if it is ErrBlockDesync for the second block header,
+// and alt, the bytes at which the header would start if pointers had the other size, is a plausible block code.
+// The header of the first block always follows the file header, but the size of a header depends on the pointer size,
+// so the second one is the first misplaced by a wrong pointer size.
+func (f *File) pointerSizeMismatch(err error, alt []byte) error {
+	if !errors.Is(err, ErrBlockDesync) || f.headersRead != 1 || len(alt) != 4 {
+		return err
+	}
+	var code [4]byte
+	copy(code[:], alt)
+	if !plausibleBlockCode(code) {
+		return err
+	}
+	other := 64
+	if f.pointerSize == 64 {
+		other = 32
+	}
+	return fmt.Errorf("%w: header declares %d bit pointers, but block '%s' is located as if they had %d bits",
+		ErrPointerSizeMismatch, f.pointerSize, byteSliceToString(code[:]), other)
+}
+
+// legacyVersion is the first version of Blender whose block codes are always padded with nulls.
+const legacyVersion = 250
+
+// blockCode interprets the code of a file-block header.
+// Files older than Blender 2.50 include some written by third party exporters, which pad codes with spaces instead of nulls,
+// hence such padding is removed for them as well.
+func (f *File) blockCode(raw [4]byte) (Code, error) {
+	if major, minor := f.Version(); major*100+minor < legacyVersion {
+		var err error
+		if raw, err = legacyBlockCode(raw); err != nil {
+			return "", err
+		}
+	}
+	if err := f.checkBlockCode(raw); err != nil {
+		return "", err
+	}
+	return Code(byteSliceToString(raw[:])), nil
+}
+
+// legacyBlockCode replaces the padding of a block code written by Blender 2.4x or a third party exporter with nulls.
+// Codes with anything but padding following their first null or space are not supported.
+func legacyBlockCode(raw [4]byte) ([4]byte, error) {
+	end := bytes.IndexAny(raw[:], "\x00 ")
+	if end == -1 {
+		return raw, nil
+	}
+	for _, c := range raw[end:] {
+		if c != 0 && c != ' ' {
+			return raw, fmt.Errorf("%w: %q", ErrUnsupportedBlockCode, raw[:])
 		}
 	}
+	var code [4]byte
+	copy(code[:], raw[:end])
+	return code, nil
+}
+
+// checkBlockCode verifies under strict validation that code consists of upper case letters and digits padded with nulls.
+// An implausible code means the size of the previous block was wrong, so the reader lost track of the block boundaries.
+func (f *File) checkBlockCode(code [4]byte) error {
+	if !f.strict || plausibleBlockCode(code) {
+		return nil
+	}
+	if f.lastHeader == nil {
+		return fmt.Errorf("%w: implausible block code %q after the file header", ErrBlockDesync, code[:])
+	}
+	return fmt.Errorf("%w: implausible block code %q after block '%s' at %#x with size %d",
+		ErrBlockDesync, code[:], f.lastHeader.Code, f.lastHeader.OldMemoryAddress, f.lastHeader.Size)
+}
+
+// plausibleBlockCode reports whether code consists of at least one upper case letter or digit followed by nulls only.
+func plausibleBlockCode(code [4]byte) bool {
+	end := bytes.IndexByte(code[:], 0)
+	if end == -1 {
+		end = len(code)
+	}
+	if end == 0 {
+		return false
+	}
+	for i, c := range code {
+		switch {
+		case i >= end && c != 0:
+			return false
+		case i < end && !(c >= 'A' && c <= 'Z' || c >= '0' && c <= '9'):
+			return false
+		}
+	}
+	return true
+}
+
+// headerPool holds the buffers file-block headers are read into, which are only needed until the header is decoded.
+var headerPool = sync.Pool{
+	New: func() interface{} {
+		b := make([]byte, 24)
+		return &b
+	},
 }
 
 func (f *File) readFileBlockHeader64() (*FileBlockHeader64, error) {
-	header := FileBlockHeader64{}
-	return &header, f.read(24, &header)
+	buf := headerPool.Get().(*[]byte)
+	defer headerPool.Put(buf)
+	data := (*buf)[:24]
+	if err := f.readFull(data); err != nil {
+		return nil, err
+	}
+	header := FileBlockHeader64{
+		Size:             f.order.Uint32(data[4:]),
+		OldMemoryAddress: f.order.Uint64(data[8:]),
+		SDNAIndex:        f.order.Uint32(data[16:]),
+		Count:            f.order.Uint32(data[20:]),
+	}
+	copy(header.Code[:], data)
+	return &header, nil
 }
+
 func (f *File) readFileBlockHeader32() (*FileBlockHeader32, error) {
-	header := FileBlockHeader32{}
-	return &header, f.read(20, &header)
+	buf := headerPool.Get().(*[]byte)
+	defer headerPool.Put(buf)
+	data := (*buf)[:20]
+	if err := f.readFull(data); err != nil {
+		return nil, err
+	}
+	header := FileBlockHeader32{
+		Size:             f.order.Uint32(data[4:]),
+		OldMemoryAddress: f.order.Uint32(data[8:]),
+		SDNAIndex:        f.order.Uint32(data[12:]),
+		Count:            f.order.Uint32(data[16:]),
+	}
+	copy(header.Code[:], data)
+	return &header, nil
 }
 
-func (f *File) getFileBlockData(name string) (io.Reader, error) {
-	if f.pointerSize == 64 {
-		b, ok := f.fileBlocks64[name]
-		if !ok {
-			return nil, fmt.Errorf("file block '%s' not found", name)
+// readFull fills data from the file.
+// This function panics if byte order has not been determined yet, which should be done when initializing File.
+func (f *File) readFull(data []byte) error {
+	if f.order == nil {
+		panic("blend: unable to read bytes before reading header")
+	}
+	_, err := io.ReadFull(f.r, data)
+	return err
+}
+
+// maxPreallocSize is the size up to which the data of a block is allocated before reading it.
+// Larger blocks grow while being read, so a corrupt size does not allocate memory for data the file does not hold.
+const maxPreallocSize = 1 << 20
+
+// readData reads the n bytes of data of a file-block.
+func (f *File) readData(n int64) ([]byte, error) {
+	// blocks like ENDB have no data, and readers differ in how they handle reads of zero bytes
+	// decompressing readers return partial reads, hence the data is read in full
+	if n <= maxPreallocSize {
+		data, err := readBytes(f.r, int(n))
+		// the header announced the data, so the file must not end here
+		if err == io.EOF {
+			err = io.ErrUnexpectedEOF
 		}
-		return bytes.NewReader(b.data), nil
+		return data, err
+	}
+	buf := bytes.NewBuffer(make([]byte, 0, maxPreallocSize))
+	read, err := io.CopyN(buf, f.r, n)
+	if read < n && (err == nil || err == io.EOF) {
+		err = io.ErrUnexpectedEOF
+	}
+	return buf.Bytes(), err
+}
+
+// skip discards the next n bytes of the file.
+func (f *File) skip(n int64) error {
+	skipped, err := io.CopyN(ioutil.Discard, f.r, n)
+	if skipped < n && (err == nil || err == io.EOF) {
+		err = io.ErrUnexpectedEOF
+	}
+	return err
+}
+
+// getFileBlockData returns a reader over the data of the first file-block with the given code.
+// The reader reads the data of the block itself rather than a copy.
+func (f *File) getFileBlockData(code Code) (*bytes.Reader, error) {
+	b, ok := f.fileBlocks[code]
+	if !ok {
+		return nil, fmt.Errorf("file block '%s' not found", code)
+	}
+	return bytes.NewReader(b[0].Data), nil
+}
+
+// BlockDataCopy returns a copy of the data of the first file-block with the given code, reading the blocks if needed.
+// Unlike the data of blocks returned by ReadAllBlocks, the copy may be modified without affecting the File.
+func (f *File) BlockDataCopy(code Code) ([]byte, error) {
+	if err := f.loadBlocks(); err != nil {
+		return nil, err
+	}
+	b, ok := f.fileBlocks[code]
+	if !ok {
+		return nil, fmt.Errorf("blend: file block '%s' not found", code)
+	}
+	return append([]byte(nil), b[0].Data...), nil
+}
+
+// blockByAddress returns the file-block which was located at addr when the file was written.
+func (f *File) blockByAddress(addr uint64) (*Block, error) {
+	b, ok := f.addresses[addr]
+	if !ok {
+		return nil, fmt.Errorf("blend: no file block at address %#x", addr)
+	}
+	return b, nil
+}
+
+// BlockOffset returns the offset from the start of the file of the header of the first file-block with the given code.
+func (f *File) BlockOffset(code Code) (int64, error) {
+	if err := f.loadBlocks(); err != nil {
+		return 0, err
 	}
-	b, ok := f.fileBlocks32[name]
+	b, ok := f.fileBlocks[code]
 	if !ok {
-		return nil, fmt.Errorf("file block '%s' not found", name)
+		return 0, fmt.Errorf("blend: file block '%s' not found", code)
+	}
+	return b[0].offset, nil
+}
+
+// offset returns the number of bytes read from the start of the file.
+func (f *File) offset() int64 {
+	if f.counter == nil {
+		return 0
+	}
+	return f.counter.n
+}
+
+// SDNA reads all file blocks if needed and returns the parsed structure DNA of the file.
+func (f *File) SDNA() (*StructureDNA, error) {
+	if f.sdna != nil {
+		return f.sdna, nil
+	}
+	if err := f.loadBlocks(); err != nil {
+		return nil, err
 	}
-	return bytes.NewReader(b.data), nil
+	sdna, err := f.readSDNA()
+	if err != nil {
+		return nil, err
+	}
+	f.sdna = sdna
+	return sdna, nil
 }
 
 func (f *File) readSDNA() (*StructureDNA, error) {
-	data, err := f.getFileBlockData("DNA1")
+	if f.metrics != nil {
+		defer track(&f.metrics.SDNA, time.Now())
+	}
+	r, err := f.getFileBlockData(CodeDNA1)
 	if err != nil {
 		return nil, err
 	}
+	data := &alignedReader{Reader: r}
 
 	fb := StructureDNA{}
 
 	// read initial data
-	err = read(data, 4, f.order, &fb.Identifier)
-	if err != nil {
+	if fb.Identifier, err = data.ReadMagic(); err != nil {
 		return nil, fmt.Errorf("blend: unable to read sdna identifier: %w", err)
 	}
-	err = read(data, 4, f.order, &fb.NameID)
-	if err != nil {
+	if fb.NameID, err = data.ReadMagic(); err != nil {
 		return nil, fmt.Errorf("blend: unable to read sdna NameID: %w", err)
 	}
 	err = read(data, 4, f.order, &fb.NumNames)
 	if err != nil {
 		return nil, fmt.Errorf("blend: unable to read sdna NumNames: %w", err)
 	}
+	if err = f.checkSDNACount("NumNames", fb.NumNames); err != nil {
+		return nil, err
+	}
+
+	fb.Names, err = readStrings(data, int(fb.NumNames))
+	if err != nil {
+		return nil, fmt.Errorf("blend: unable to read sdna Names: %w", err)
+	}
+
+	// sections following a variable length section are aligned to 4 bytes
+	if err = data.Align(4); err != nil {
+		return nil, fmt.Errorf("blend: unable to read sdna TypeID: %w", err)
+	}
+	if fb.TypeID, err = data.ReadMagic(); err != nil {
+		return nil, fmt.Errorf("blend: unable to read sdna TypeID: %w", err)
+	}
+	err = read(data, 4, f.order, &fb.NumTypes)
+	if err != nil {
+		return nil, fmt.Errorf("blend: unable to read sdna NumTypes: %w", err)
+	}
+	if err = f.checkSDNACount("NumTypes", fb.NumTypes); err != nil {
+		return nil, err
+	}
+	fb.Types, err = readStrings(data, int(fb.NumTypes))
+	if err != nil {
+		return nil, fmt.Errorf("blend: unable to read sdna Types: %w", err)
+	}
+
+	if err = data.Align(4); err != nil {
+		return nil, fmt.Errorf("blend: unable to read sdna LenID: %w", err)
+	}
+	if fb.LenID, err = data.ReadMagic(); err != nil {
+		return nil, fmt.Errorf("blend: unable to read sdna LenID: %w", err)
+	}
+	fb.Lengths = make([]uint16, fb.NumTypes)
+	err = read(data, 2*int(fb.NumTypes), f.order, fb.Lengths)
+	if err != nil {
+		return nil, fmt.Errorf("blend: unable to read sdna Lengths: %w", err)
+	}
+
+	if err = data.Align(4); err != nil {
+		return nil, fmt.Errorf("blend: unable to read sdna StructID: %w", err)
+	}
+	if fb.StructID, err = data.ReadMagic(); err != nil {
+		return nil, fmt.Errorf("blend: unable to read sdna StructID: %w", err)
+	}
+	err = read(data, 4, f.order, &fb.NumStructs)
+	if err != nil {
+		return nil, fmt.Errorf("blend: unable to read sdna NumStructs: %w", err)
+	}
+	if err = f.checkSDNACount("NumStructs", fb.NumStructs); err != nil {
+		return nil, err
+	}
+	fb.Structs = make([]DNAStruct, fb.NumStructs)
+	for i := range fb.Structs {
+		s := &fb.Structs[i]
+		err = read(data, 2, f.order, &s.TypeIdx)
+		if err != nil {
+			return nil, fmt.Errorf("blend: unable to read sdna struct %d: %w", i, err)
+		}
+		err = read(data, 2, f.order, &s.NumFields)
+		if err != nil {
+			return nil, fmt.Errorf("blend: unable to read sdna struct %d: %w", i, err)
+		}
+		s.Fields = make([]DNAField, s.NumFields)
+		err = read(data, 4*int(s.NumFields), f.order, s.Fields)
+		if err != nil {
+			return nil, fmt.Errorf("blend: unable to read sdna fields of struct %d: %w", i, err)
+		}
+	}
+
+	if err = fb.init(int(f.pointerSize) / 8); err != nil {
+		return nil, err
+	}
+	return &fb, nil
+}
+
+// SDNANames calls yield with the index and value of each name of the SDNA, stopping early if yield returns false.
+// Unlike SDNA it does not retain the names, which reduces allocations if only a few of them are needed.
+func (f *File) SDNANames(yield func(idx int, name string) bool) error {
+	if err := f.loadBlocks(); err != nil {
+		return err
+	}
+	blocks, ok := f.fileBlocks[CodeDNA1]
+	if !ok {
+		return fmt.Errorf("file block '%s' not found", CodeDNA1)
+	}
+	data := blocks[0].Data
+	if len(data) < 12 {
+		return errors.New("blend: unable to read sdna NumNames: unexpected end of data")
+	}
+	numNames := f.order.Uint32(data[8:12])
+	if err := f.checkSDNACount("NumNames", numNames); err != nil {
+		return err
+	}
+
+	pos := 12
+	for idx := 0; idx < int(numNames); idx++ {
+		end := bytes.IndexByte(data[pos:], 0)
+		if end == -1 {
+			return fmt.Errorf("blend: unable to read sdna name %d: unexpected end of data", idx)
+		}
+		if !yield(idx, string(data[pos:pos+end])) {
+			return nil
+		}
+		pos += end + 1
+	}
+	return nil
+}
+
+// defaultMaxSDNAEntries bounds the number of names, types and structs of the SDNA unless set by WithMaxSDNAEntries.
+// Real files have a few thousand entries each, so larger counts indicate corruption or a wrongly detected byte order.
+const defaultMaxSDNAEntries = 100000
+
+// checkSDNACount returns ErrMalformedSDNA if count exceeds the maximum number of SDNA entries.
+func (f *File) checkSDNACount(name string, count uint32) error {
+	if int64(count) > int64(f.maxSDNAEntries) {
+		return fmt.Errorf("%w: %s of %d exceeds %d", ErrMalformedSDNA, name, count, f.maxSDNAEntries)
+	}
+	return nil
+}
 
-	names := make([]string, fb.NumNames)
-	currName := strings.Builder{}
-	for namesIdx := 0; namesIdx < int(fb.NumNames); {
-		binData, err := readNextBytes(data, 1)
+// readStrings reads `n` null-terminated strings.
+func readStrings(r io.Reader, n int) ([]string, error) {
+	s := make([]string, n)
+	curr := strings.Builder{}
+	for idx := 0; idx < n; {
+		binData, err := readNextBytes(r, 1)
 		if err != nil {
 			return nil, err
 		}
 		if binData[0] == '\x00' {
-			names[namesIdx] = currName.String()
-			namesIdx++
-			currName.Reset()
+			s[idx] = curr.String()
+			idx++
+			curr.Reset()
 			continue
 		}
-		currName.Write(binData)
+		curr.Write(binData)
 	}
-	fb.Names = names
+	return s, nil
+}
 
-	return &fb, nil
+// countingReader keeps track of the number of bytes read from the underlying reader.
+type countingReader struct {
+	r io.Reader
+	n int64
+}
+
+func (c *countingReader) Read(p []byte) (int, error) {
+	n, err := c.r.Read(p)
+	c.n += int64(n)
+	return n, err
 }
 
 // read reads the next `n` bytes into the structured `data`.
@@ -184,8 +833,18 @@ func (f *File) read(n int, data interface{}) error {
 }
 
 // read reads `n` bytes from reader and parses it into `data`.
+// Like binary.Read, data must be a pointer to a fixed-size value or a slice of fixed-size values:
+// a number, bool, or an array or struct made up of those only. Types of variable size like
+// StructureDNA, which holds slices, are rejected with ErrUnreadableType before reading any bytes.
 func read(r io.Reader, n int, order binary.ByteOrder, data interface{}) error {
-	binData, err := readNextBytes(r, n)
+	if !readable(data) {
+		return fmt.Errorf("%w: %T", ErrUnreadableType, data)
+	}
+	// block data is read through a bytes.Reader, which tells how many bytes are missing
+	if br, ok := r.(interface{ Len() int }); ok && br.Len() < n {
+		return fmt.Errorf("%w: %d bytes exceed the remaining %d bytes by %d bytes", ErrShortBlockData, n, br.Len(), n-br.Len())
+	}
+	binData, err := readBytes(r, n)
 	if err != nil {
 		return err
 	}
@@ -194,6 +853,15 @@ func read(r io.Reader, n int, order binary.ByteOrder, data interface{}) error {
 	return binary.Read(buffer, order, data)
 }
 
+// readable reports whether binary.Read is able to decode into data.
+func readable(data interface{}) bool {
+	switch reflect.ValueOf(data).Kind() {
+	case reflect.Ptr, reflect.Slice:
+		return binary.Size(data) >= 0
+	}
+	return false
+}
+
 // readNextBytes reads number of bytes from file.
 // shamelessly stolen from https://www.jonathan-petitcolas.com/2014/09/25/parsing-binary-files-in-go.html
 func readNextBytes(r io.Reader, n int) ([]byte, error) {
@@ -208,6 +876,22 @@ func readNextBytes(r io.Reader, n int) ([]byte, error) {
 	return bytes, nil
 }
 
+// readBytes reads exactly n bytes of a variable-length payload, like the data of a block, from r.
+// Unlike readNextBytes it keeps reading until n bytes are read, as readers may return less than requested.
+// Reading zero bytes never touches r. Like io.ReadFull, it returns io.EOF if r ends before the first byte
+// and io.ErrUnexpectedEOF if it ends within the payload.
+func readBytes(r io.Reader, n int) ([]byte, error) {
+	if n < 0 {
+		return nil, fmt.Errorf("blend: unable to read %d bytes", n)
+	}
+	data := make([]byte, n)
+	if n == 0 {
+		return data, nil
+	}
+	read, err := io.ReadFull(r, data)
+	return data[:read], err
+}
+
 func byteSliceToString(s []byte) string {
 	n := bytes.IndexByte(s, 0)
 	// if byte array doesn't contain any 0 bytes
@@ -3,12 +3,16 @@ package blend
 import (
 	"bytes"
 	"encoding/binary"
+	"errors"
 	"fmt"
 	"io"
+	"io/ioutil"
 	"os"
 	"path/filepath"
+	"reflect"
 	"sort"
 	"testing"
+	"testing/iotest"
 )
 
 func TestNewFile_readExampleHeader(t *testing.T) {
@@ -128,6 +132,95 @@ func TestFile_readPanic(t *testing.T) {
 	}
 }
 
+func TestRead_unreadableType(t *testing.T) {
+	var iface interface{}
+	var sdna StructureDNA
+	var n uint32
+	for _, data := range []interface{}{&iface, &sdna, n, nil} {
+		r := bytes.NewReader([]byte{1, 2, 3, 4})
+		if err := read(r, 4, binary.LittleEndian, data); !errors.Is(err, ErrUnreadableType) {
+			t.Errorf("expected ErrUnreadableType reading into %T, got: %v", data, err)
+		}
+		if r.Len() != 4 {
+			t.Errorf("expected no bytes read into %T, %d were read", data, 4-r.Len())
+		}
+	}
+
+	if err := read(bytes.NewReader([]byte{1, 2, 3, 4}), 4, binary.LittleEndian, &n); err != nil || n != 0x04030201 {
+		t.Errorf("expected 0x04030201, got %#x with error: %v", n, err)
+	}
+}
+
+func TestReadBytes(t *testing.T) {
+	// readers may return fewer bytes than requested
+	data, err := readBytes(iotest.OneByteReader(bytes.NewReader([]byte{1, 2, 3, 4, 5})), 4)
+	if err != nil {
+		t.Fatalf("Expected nil error, got: %v", err)
+	}
+	if !bytes.Equal(data, []byte{1, 2, 3, 4}) {
+		t.Errorf("expected [1 2 3 4], got: %v", data)
+	}
+
+	for _, tt := range []struct {
+		data []byte
+		n    int
+		err  error
+	}{
+		{[]byte{1, 2}, 4, io.ErrUnexpectedEOF},
+		{nil, 4, io.EOF},
+		{nil, 0, nil},
+	} {
+		data, err := readBytes(bytes.NewReader(tt.data), tt.n)
+		if err != tt.err {
+			t.Errorf("expected error %v reading %d of %d bytes, got: %v", tt.err, tt.n, len(tt.data), err)
+		}
+		if len(data) != len(tt.data) {
+			t.Errorf("expected the %d bytes read, got: %v", len(tt.data), data)
+		}
+	}
+
+	if _, err := readBytes(bytes.NewReader(nil), -1); err == nil {
+		t.Error("expected error reading a negative number of bytes")
+	}
+}
+
+func TestNewFile_errorKinds(t *testing.T) {
+	for _, tt := range []struct {
+		name  string
+		r     io.Reader
+		opts  []Option
+		kind  ErrorKind
+		cause error
+	}{
+		{"read failure", iotest.TimeoutReader(iotest.OneByteReader(bytes.NewReader(header('-', 'v', "280")))), nil, KindIO, iotest.ErrTimeout},
+		{"short header", bytes.NewReader(header('-', 'v', "28")), nil, KindFormat, ErrShortHeader},
+		{"empty", bytes.NewReader(nil), nil, KindFormat, ErrShortHeader},
+		{"identifier", bytes.NewReader(rawHeader("NOBLEND", '-', 'v', "280")), nil, KindFormat, ErrInvalidIdentifier},
+		{"endianness", bytes.NewReader(header('-', 'x', "280")), nil, KindFormat, ErrInvalidEndianness},
+		{"version", bytes.NewReader(header('-', 'v', "2.8")), []Option{WithStrictValidation()}, KindValidation, ErrInvalidVersion},
+	} {
+		_, err := NewFile(tt.r, tt.opts...)
+		var openErr *OpenError
+		if !errors.As(err, &openErr) {
+			t.Errorf("%s: expected *OpenError, got: %v", tt.name, err)
+			continue
+		}
+		if openErr.Kind != tt.kind {
+			t.Errorf("%s: expected kind %v, got %v", tt.name, tt.kind, openErr.Kind)
+		}
+		if !errors.Is(err, tt.cause) {
+			t.Errorf("%s: expected error to wrap %v, got: %v", tt.name, tt.cause, err)
+		}
+	}
+
+	if _, err := NewFile(bytes.NewReader(header('x', 'v', "280")), WithStrictValidation()); err != nil {
+		t.Errorf("expected invalid pointer size to be left to Validate, got: %v", err)
+	}
+	if _, err := NewFile(bytes.NewReader(header('-', 'v', "2.8"))); err != nil {
+		t.Errorf("expected invalid version to be accepted without strict validation, got: %v", err)
+	}
+}
+
 func TestNewFile_headerInvalidIdentifier(t *testing.T) {
 	f := bytes.NewBuffer(rawHeader("NOBLEND", '-', 'v', "280"))
 	_, err := NewFile(f)
@@ -140,6 +233,26 @@ func TestNewFile_headerInvalidIdentifier(t *testing.T) {
 	}
 }
 
+func TestNewFile_headerOneByteReader(t *testing.T) {
+	f, err := NewFile(iotest.OneByteReader(bytes.NewReader(header('_', 'V', "279"))))
+	if err != nil {
+		t.Fatalf("Expected nil error, got: %v", err)
+	}
+	if f.pointerSize != 32 || f.order != binary.BigEndian || string(f.header.Version[:]) != "279" {
+		t.Errorf("expected 32 bit big endian header of version 279, got: %+v", f.header)
+	}
+}
+
+func TestNewFile_shortHeader(t *testing.T) {
+	_, err := NewFile(iotest.OneByteReader(bytes.NewReader([]byte("BLENDER-"))))
+	if !errors.Is(err, ErrShortHeader) {
+		t.Errorf("expected ErrShortHeader, got: %v", err)
+	}
+	if err != nil && err.Error() != "blend: short header read" {
+		t.Errorf("expected error 'blend: short header read', got: '%s'", err)
+	}
+}
+
 func TestNewFile_readExampleFirstFileHeader(t *testing.T) {
 	name := "cubus-animated.blend"
 	r, err := readExample(name)
@@ -199,10 +312,10 @@ func TestNewFile_readExampleAllFileBlocks(t *testing.T) {
 	if err := f.readFileBlocks(); err != nil {
 		t.Errorf("Expected nil error, got: %v", err)
 	}
-	keys := make([]string, len(f.fileBlocks64))
+	keys := make([]string, len(f.fileBlocks))
 	i := 0
-	for k := range f.fileBlocks64 {
-		keys[i] = k
+	for k := range f.fileBlocks {
+		keys[i] = string(k)
 		i++
 	}
 	sort.Strings(keys)
@@ -248,6 +361,359 @@ func TestNewFile_readExampleSDNA(t *testing.T) {
 	fmt.Printf("reader_test sdna: %#v\n", sdna)
 }
 
+func TestFile_BlockOffset(t *testing.T) {
+	f := readExampleFile(t, "cubus-animated.blend")
+
+	offset, err := f.BlockOffset("REND")
+	if err != nil {
+		t.Fatalf("Expected nil error, got: %v", err)
+	}
+	if offset != 12 {
+		t.Errorf("expected first block at offset 12, got %d", offset)
+	}
+
+	// each block directly follows the data of the previous one
+	expected := int64(12)
+	for _, b := range f.blocks {
+		if b.offset != expected {
+			t.Fatalf("expected block '%s' at offset %d, got %d", b.Header.Code, expected, b.offset)
+		}
+		expected += 24 + int64(b.Header.Size)
+	}
+
+	if _, err := f.BlockOffset("NONE"); err == nil {
+		t.Error("expected error for missing block code")
+	}
+}
+
+func TestFile_Version(t *testing.T) {
+	f, err := NewFile(bytes.NewBuffer(header('-', 'v', "405")))
+	if err != nil {
+		t.Fatalf("Expected nil error, got: %v", err)
+	}
+	if major, minor := f.Version(); major != 4 || minor != 5 {
+		t.Errorf("expected version 4.5, got %d.%d", major, minor)
+	}
+}
+
+// zeroReadRejecter fails reads into empty buffers, which a reader is free to do.
+type zeroReadRejecter struct {
+	r io.Reader
+}
+
+func (z zeroReadRejecter) Read(p []byte) (int, error) {
+	if len(p) == 0 {
+		return 0, errors.New("read of zero bytes")
+	}
+	return z.r.Read(p)
+}
+
+func TestFile_readFileBlocksZeroSize(t *testing.T) {
+	fx := newFixture(t)
+	fx.addRaw("TEST", 0, 0, nil)
+
+	for name, r := range map[string]io.Reader{
+		"bytes.Reader":     bytes.NewReader(fx.bytes()),
+		"zeroReadRejecter": zeroReadRejecter{bytes.NewReader(fx.bytes())},
+	} {
+		t.Run(name, func(t *testing.T) {
+			f, err := NewFile(r)
+			if err != nil {
+				t.Fatalf("Expected nil error, got: %v", err)
+			}
+			if err := f.readFileBlocks(); err != nil {
+				t.Fatalf("Expected nil error, got: %v", err)
+			}
+			if len(f.blocks) != len(fx.blocks) {
+				t.Errorf("expected %d blocks, got %d", len(fx.blocks), len(f.blocks))
+			}
+			tests := f.fileBlocks["TEST"]
+			if len(tests) != 2 || tests[1].Data == nil || len(tests[1].Data) != 0 {
+				t.Errorf("expected empty data for zero size block, got: %v", tests)
+			}
+			if _, ok := f.fileBlocks[CodeEnd]; !ok {
+				t.Error("expected ENDB block")
+			}
+		})
+	}
+}
+
+func TestFile_TrailingData(t *testing.T) {
+	fx := newFixture(t)
+	data := append(fx.bytes(), "METADATA\x00\x01"...)
+
+	f := parseFile(t, data)
+	if trailing := string(f.TrailingData()); trailing != "METADATA\x00\x01" {
+		t.Errorf("expected trailing data 'METADATA\\x00\\x01', got: %q", trailing)
+	}
+	if len(f.blocks) != len(fx.blocks) {
+		t.Errorf("expected %d blocks, got %d", len(fx.blocks), len(f.blocks))
+	}
+
+	example := readExampleFile(t, "cubus-animated.blend")
+	if trailing := example.TrailingData(); trailing != nil {
+		t.Errorf("expected no trailing data, got: %q", trailing)
+	}
+}
+
+func TestFile_readBlockVerbatim(t *testing.T) {
+	fx := newFixture(t)
+	fx.f.header.Version = [3]byte{'4', '0', '0'}
+	// data starting like a zstd frame is no compressed block, Blender only compresses whole files
+	data := []byte{0x28, 0xb5, 0x2f, 0xfd, 1, 2, 3, 4}
+	fx.addRaw(CodeData, 0, 1, data)
+
+	f := fx.file(WithStrictValidation())
+	blocks := f.fileBlocks[CodeData]
+	if b := blocks[len(blocks)-1]; !bytes.Equal(b.Data, data) || b.Header.Size != uint32(len(data)) {
+		t.Errorf("expected block data %v to be read verbatim, got %v of size %d", data, b.Data, b.Header.Size)
+	}
+}
+
+func TestFile_Endianness(t *testing.T) {
+	f := readExampleFile(t, "cubus-animated.blend")
+	if e := f.Endianness(); e != Little || e.String() != "little endian" {
+		t.Errorf("expected little endian, got: %v", e)
+	}
+	if size := f.PointerSize(); size != 8 {
+		t.Errorf("expected pointer size 8, got: %d", size)
+	}
+
+	f, err := NewFile(bytes.NewBuffer(header('_', 'V', "280")))
+	if err != nil {
+		t.Fatalf("Expected nil error, got: %v", err)
+	}
+	if e := f.Endianness(); e != Big || e.String() != "big endian" {
+		t.Errorf("expected big endian, got: %v", e)
+	}
+	if size := f.PointerSize(); size != 4 {
+		t.Errorf("expected pointer size 4, got: %d", size)
+	}
+}
+
+// legacyFile serializes a 32-bit file of the given version holding blocks with the given raw codes.
+func legacyFile(version string, codes ...string) []byte {
+	buf := bytes.NewBuffer(header('_', 'v', version))
+	for _, code := range append(codes, "ENDB") {
+		b := &Block{Header: BlockHeader{Code: Code(code), Size: 4}, Data: make([]byte, 4)}
+		if code == "ENDB" {
+			b.Header.Size, b.Data = 0, nil
+		}
+		writeBlock(buf, binary.LittleEndian, 32, b)
+	}
+	return buf.Bytes()
+}
+
+func TestFile_legacyBlockCodes(t *testing.T) {
+	data := legacyFile("249", "SC\x00\x00", "SC  ", "OB\x00 ", "DATA", "GLOB")
+
+	for _, opts := range [][]Option{nil, {WithStrictValidation()}} {
+		f := parseFile(t, data, opts...)
+		var codes []Code
+		for _, b := range f.blocks {
+			codes = append(codes, b.Header.Code)
+		}
+		expected := []Code{CodeScene, CodeScene, CodeObject, CodeData, CodeGlobal, CodeEnd}
+		if !reflect.DeepEqual(codes, expected) {
+			t.Errorf("expected codes %q, got %q", expected, codes)
+		}
+	}
+
+	// codes of current files are taken as is
+	f := parseFile(t, legacyFile("280", "SC  "))
+	if code := f.blocks[0].Header.Code; code != "SC  " {
+		t.Errorf("expected code 'SC  ', got %q", code)
+	}
+}
+
+func TestFile_legacyBlockCodeUnsupported(t *testing.T) {
+	f, err := NewFile(bytes.NewReader(legacyFile("249", "S\x00C\x00")))
+	if err != nil {
+		t.Fatalf("Expected nil error, got: %v", err)
+	}
+	if err := f.loadBlocks(); !errors.Is(err, ErrUnsupportedBlockCode) {
+		t.Errorf("expected ErrUnsupportedBlockCode, got: %v", err)
+	}
+}
+
+func TestFile_BlockDataCopy(t *testing.T) {
+	f := readExampleFile(t, "cubus-animated.blend")
+
+	data, err := f.BlockDataCopy(CodeObject)
+	if err != nil {
+		t.Fatalf("Expected nil error, got: %v", err)
+	}
+	original := append([]byte(nil), data...)
+	for i := range data {
+		data[i] = 0xff
+	}
+	again, err := f.BlockDataCopy(CodeObject)
+	if err != nil {
+		t.Fatalf("Expected nil error, got: %v", err)
+	}
+	if !bytes.Equal(again, original) {
+		t.Error("expected modifying the copy to leave the block data unchanged")
+	}
+	if name, err := f.FieldBytes(CodeObject, 0, "id.name"); err != nil || byteSliceToString(name) != "OBCamera" {
+		t.Errorf("expected name 'OBCamera' after modifying the copy, got %q: %v", name, err)
+	}
+
+	if _, err := f.BlockDataCopy("XX"); err == nil {
+		t.Error("expected error for unknown block code")
+	}
+}
+
+func TestFile_BlockCount(t *testing.T) {
+	r, err := readExample("cubus-animated.blend")
+	if err != nil {
+		t.Fatalf("Unable to read example file: %s", err)
+	}
+	defer r.Close()
+	f, err := NewFile(r)
+	if err != nil {
+		t.Fatalf("Expected nil error, got: %v", err)
+	}
+	if n := f.BlockCount(CodeObject); n != 0 {
+		t.Errorf("expected no blocks before reading them, got %d", n)
+	}
+	if _, err := f.ReadAllBlocks(); err != nil {
+		t.Fatalf("Expected nil error, got: %v", err)
+	}
+
+	testTable := map[Code]int{
+		CodeData:   1320,
+		CodeObject: 3,
+		CodeMesh:   1,
+		CodeEnd:    1,
+		CodeText:   0,
+	}
+	for code, expected := range testTable {
+		if n := f.BlockCount(code); n != expected {
+			t.Errorf("expected %d blocks with code '%s', got %d", expected, code, n)
+		}
+	}
+}
+
+func TestFile_readBlockHugeSize(t *testing.T) {
+	data := header('_', 'v', "280")
+	buf := bytes.NewBuffer(data)
+	writeBlock(buf, binary.LittleEndian, 32, &Block{Header: BlockHeader{Code: CodeData, Size: 0xfffffff0}, Data: []byte{1, 2, 3}})
+
+	f, err := NewFile(buf)
+	if err != nil {
+		t.Fatalf("Expected nil error, got: %v", err)
+	}
+	if err := f.loadBlocks(); !errors.Is(err, io.ErrUnexpectedEOF) {
+		t.Errorf("expected io.ErrUnexpectedEOF, got: %v", err)
+	}
+}
+
+func TestSafeSliceOverflow(t *testing.T) {
+	if _, err := safeSlice(make([]byte, 10), 5, int(^uint(0)>>1)); !errors.Is(err, ErrShortBlockData) {
+		t.Errorf("expected ErrShortBlockData, got: %v", err)
+	}
+	if _, err := safeSlice(make([]byte, 10), 11, 0); !errors.Is(err, ErrShortBlockData) {
+		t.Errorf("expected ErrShortBlockData, got: %v", err)
+	}
+}
+
+func TestReadHeader(t *testing.T) {
+	r, err := readExample("cubus-animated.blend")
+	if err != nil {
+		t.Fatalf("Unable to read example file: %s", err)
+	}
+	defer r.Close()
+
+	h, endianness, pointerSize, err := ReadHeader(r)
+	if err != nil {
+		t.Fatalf("Expected nil error, got: %v", err)
+	}
+	expected := FileHeader{
+		Identifier:  [7]byte{'B', 'L', 'E', 'N', 'D', 'E', 'R'},
+		PointerSize: '-',
+		Endianness:  'v',
+		Version:     [3]byte{'2', '8', '0'},
+	}
+	if h != expected {
+		t.Errorf("expected header %+v, got %+v", expected, h)
+	}
+	if endianness != Little || pointerSize != 64 {
+		t.Errorf("expected little endian with 64 bit pointers, got %v with %d bit pointers", endianness, pointerSize)
+	}
+
+	_, endianness, pointerSize, err = ReadHeader(bytes.NewReader(header('_', 'V', "249")))
+	if err != nil {
+		t.Fatalf("Expected nil error, got: %v", err)
+	}
+	if endianness != Big || pointerSize != 32 {
+		t.Errorf("expected big endian with 32 bit pointers, got %v with %d bit pointers", endianness, pointerSize)
+	}
+}
+
+func TestReadHeader_invalid(t *testing.T) {
+	testTable := map[string]struct {
+		data     []byte
+		expected error
+	}{
+		"short":      {header('-', 'v', "28"), ErrShortHeader},
+		"endianness": {header('-', 'x', "280"), ErrInvalidEndianness},
+		"identifier": {rawHeader("BLANDER", '-', 'v', "280"), ErrInvalidIdentifier},
+	}
+	for name, tt := range testTable {
+		t.Run(name, func(t *testing.T) {
+			if _, _, _, err := ReadHeader(bytes.NewReader(tt.data)); !errors.Is(err, tt.expected) {
+				t.Errorf("expected %v, got: %v", tt.expected, err)
+			}
+		})
+	}
+}
+
+func TestFile_ReadAllBlocks(t *testing.T) {
+	f := readExampleFile(t, "cubus-animated.blend")
+
+	blocks, err := f.ReadAllBlocks()
+	if err != nil {
+		t.Fatalf("Expected nil error, got: %v", err)
+	}
+	if len(blocks) == 0 || blocks[0].Header.Code != CodeRender || blocks[len(blocks)-1].Header.Code != CodeEnd {
+		t.Errorf("expected blocks from REND to ENDB, got %d blocks", len(blocks))
+	}
+}
+
+func TestFile_DeclaredSize(t *testing.T) {
+	f := readExampleFile(t, "cubus-animated.blend")
+
+	size, err := f.DeclaredSize()
+	if err != nil {
+		t.Fatalf("Expected nil error, got: %v", err)
+	}
+	info, err := os.Stat(filepath.Join("./examples", "cubus-animated.blend"))
+	if err != nil {
+		t.Fatalf("Expected nil error, got: %v", err)
+	}
+	if size != info.Size() {
+		t.Errorf("expected declared size %d, got %d", info.Size(), size)
+	}
+}
+
+func BenchmarkFile_readFileBlocks(b *testing.B) {
+	data, err := ioutil.ReadFile(filepath.Join("./examples", "cubus-animated.blend"))
+	if err != nil {
+		b.Fatal(err)
+	}
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		f, err := NewFile(bytes.NewReader(data))
+		if err != nil {
+			b.Fatal(err)
+		}
+		if err := f.readFileBlocks(); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
 func header(pointerSize, endianness byte, version string) []byte {
 	return rawHeader("BLENDER", pointerSize, endianness, version)
 }
@@ -262,3 +728,22 @@ func rawHeader(identifier string, pointerSize byte, endianness byte, version str
 func readExample(name string) (io.ReadCloser, error) {
 	return os.Open(filepath.Join("./examples", name))
 }
+
+// readExampleFile initializes a File from the example and reads all of its file blocks.
+func readExampleFile(t testing.TB, name string) *File {
+	t.Helper()
+	r, err := readExample(name)
+	if err != nil {
+		t.Fatalf("Unable to read example file '%s': %s", name, err)
+	}
+	defer r.Close()
+
+	f, err := NewFile(r)
+	if err != nil {
+		t.Fatalf("Expected nil error, got: %v", err)
+	}
+	if err := f.loadBlocks(); err != nil {
+		t.Fatalf("Expected nil error, got: %v", err)
+	}
+	return f
+}
@@ -0,0 +1,68 @@
+package blend
+
+import (
+	"errors"
+	"fmt"
+	"io"
+)
+
+// Rewind drops all blocks read so far along with everything derived from them, like the SDNA and decoded blocks,
+// and moves back to the first block so they are read again when needed.
+// This requires the File to be created by Open or from an io.Seeker, and allows reading a file again after Release.
+func (f *File) Rewind() error {
+	if f.rewind == nil {
+		return errors.New("blend: unable to rewind reader which does not implement io.Seeker")
+	}
+	r, err := f.rewind()
+	if err != nil {
+		return fmt.Errorf("blend: unable to rewind: %w", err)
+	}
+	f.counter.r = r
+	f.counter.n = 0
+	// the header was already read and can not change
+	if err := f.skip(12); err != nil {
+		return fmt.Errorf("blend: unable to rewind: %w", err)
+	}
+	f.reset()
+	f.released = false
+	return nil
+}
+
+// Release drops all blocks read so far along with everything derived from them to free their memory.
+// Methods reading blocks return ErrReleased until the File is rewound with Rewind.
+func (f *File) Release() {
+	f.reset()
+	f.released = true
+}
+
+// reset drops all blocks and caches built from them, leaving the File as if no block had been read.
+func (f *File) reset() {
+	f.blocks = nil
+	f.fileBlocks = make(map[Code][]*Block)
+	f.addresses = make(map[uint64]*Block)
+	f.blocksRead = false
+	f.trailing = nil
+	f.lastHeader = nil
+	f.headersRead = 0
+	f.firstTail = nil
+	f.sdna = nil
+	f.resetDecodeCache()
+}
+
+// seekerRewind returns a function moving r back to its current position.
+func seekerRewind(r io.Reader) func() (io.Reader, error) {
+	s, ok := r.(io.Seeker)
+	if !ok {
+		return nil
+	}
+	start, err := s.Seek(0, io.SeekCurrent)
+	if err != nil {
+		return nil
+	}
+	return func() (io.Reader, error) {
+		if _, err := s.Seek(start, io.SeekStart); err != nil {
+			return nil, err
+		}
+		return r, nil
+	}
+}
@@ -0,0 +1,92 @@
+package blend
+
+import (
+	"bytes"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestFile_Rewind(t *testing.T) {
+	name := "cubus-animated.blend"
+	r, err := readExample(name)
+	if err != nil {
+		t.Fatalf("Unable to read example file '%s': %s", name, err)
+	}
+	defer r.Close()
+	f, err := NewFile(r)
+	if err != nil {
+		t.Fatalf("Expected nil error, got: %v", err)
+	}
+	before, err := f.SDNA()
+	if err != nil {
+		t.Fatalf("Expected nil error, got: %v", err)
+	}
+	blocks := f.blocks
+	decoded, err := f.DecodeBlock(CodeObject)
+	if err != nil {
+		t.Fatalf("Expected nil error, got: %v", err)
+	}
+
+	if err := f.Rewind(); err != nil {
+		t.Fatalf("Expected nil error, got: %v", err)
+	}
+	if f.blocksRead || len(f.blocks) != 0 || len(f.addresses) != 0 || f.decoded != nil {
+		t.Error("expected blocks and caches to be dropped by Rewind")
+	}
+	after, err := f.SDNA()
+	if err != nil {
+		t.Fatalf("Expected nil error, got: %v", err)
+	}
+	if after == before {
+		t.Error("expected SDNA to be parsed again after Rewind")
+	}
+	if !reflect.DeepEqual(after, before) {
+		t.Error("expected SDNA parsed after Rewind to equal the one parsed before")
+	}
+	if len(f.blocks) != len(blocks) {
+		t.Fatalf("expected %d blocks after Rewind, got %d", len(blocks), len(f.blocks))
+	}
+	for i, b := range f.blocks {
+		if b == blocks[i] || !b.Equal(blocks[i]) || b.offset != blocks[i].offset {
+			t.Errorf("expected block %d to be read again identically", i)
+		}
+	}
+	again, err := f.DecodeBlock(CodeObject)
+	if err != nil {
+		t.Fatalf("Expected nil error, got: %v", err)
+	}
+	if !reflect.DeepEqual(again, decoded) {
+		t.Error("expected identical decode after Rewind")
+	}
+}
+
+func TestFile_Release(t *testing.T) {
+	data := newFixture(t).bytes()
+	f := parseFile(t, data)
+
+	f.Release()
+	if f.blocks != nil || f.sdna != nil {
+		t.Error("expected blocks and SDNA to be dropped by Release")
+	}
+	if _, err := f.SDNA(); !errors.Is(err, ErrReleased) {
+		t.Errorf("expected ErrReleased, got: %v", err)
+	}
+	if err := f.Rewind(); err != nil {
+		t.Fatalf("Expected nil error, got: %v", err)
+	}
+	if err := f.Validate(); err != nil {
+		t.Errorf("Expected nil error, got: %v", err)
+	}
+}
+
+func TestFile_RewindUnseekable(t *testing.T) {
+	data := newFixture(t).bytes()
+	f, err := NewFile(bytes.NewBuffer(data))
+	if err != nil {
+		t.Fatalf("Expected nil error, got: %v", err)
+	}
+	if err := f.Rewind(); err == nil {
+		t.Error("expected error rewinding a reader which can not seek")
+	}
+}
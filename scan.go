@@ -0,0 +1,112 @@
+//go:build go1.16
+// +build go1.16
+
+package blend
+
+import (
+	"bufio"
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"path"
+)
+
+// FileInfo describes a blend file found by ScanDir.
+type FileInfo struct {
+	// Slash separated path of the file within the scanned file system
+	Path string
+	// Compression of the file, "" meaning uncompressed, see Sniff
+	Compression string
+	// Whether the header was read, which is not the case for files compressed with zstd
+	// and files which failed to read. Header, Endianness and PointerSize are unknown and left zero otherwise.
+	HeaderRead bool
+	// Header of the file
+	Header FileHeader
+	// Byte order of the file
+	Endianness Endianness
+	// Size of a pointer in bits
+	PointerSize int
+	// Error reading the file, e.g. for a corrupt gzip stream, or nil
+	Err error
+}
+
+// Version returns the version of Blender the file was saved with, e.g. 2 and 80 for Blender 2.80.
+// It reports false if the header was not read or holds no valid version.
+func (i FileInfo) Version() (major, minor int, ok bool) {
+	v := i.Header.Version
+	if !i.HeaderRead || !isDigits(v[:]) {
+		return 0, 0, false
+	}
+	return int(v[0] - '0'), int(v[1]-'0')*10 + int(v[2]-'0'), true
+}
+
+// ScanDir walks the directory root of fsys and reads the header of every file with the extension .blend,
+// decompressing gzip compressed files. Files which turn out not to be blend files are skipped,
+// while files which fail to read are returned with Err set rather than ending the scan.
+// Only the header of each file is read, which makes this suitable for cataloguing many files.
+func ScanDir(fsys fs.FS, root string) ([]FileInfo, error) {
+	var infos []FileInfo
+	err := fs.WalkDir(fsys, root, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() || path.Ext(p) != ".blend" {
+			return nil
+		}
+		info, ok, err := scanFile(fsys, p)
+		if err != nil {
+			info.Err = fmt.Errorf("blend: unable to scan '%s': %w", p, err)
+		}
+		if ok || err != nil {
+			infos = append(infos, info)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return infos, nil
+}
+
+// scanFile reads the header of the file at p, reporting whether it is a blend file.
+// On error the returned FileInfo holds the path and the compression if it was detected.
+func scanFile(fsys fs.FS, p string) (FileInfo, bool, error) {
+	info := FileInfo{Path: p}
+	file, err := fsys.Open(p)
+	if err != nil {
+		return info, false, err
+	}
+	defer file.Close()
+
+	br := bufio.NewReader(file)
+	ok, compression, err := Sniff(br)
+	if err != nil || !ok {
+		return info, false, err
+	}
+	info.Compression = compression
+	var r io.Reader = br
+	switch compression {
+	case CompressionGzip:
+		gz, err := gzip.NewReader(br)
+		if err != nil {
+			return info, false, err
+		}
+		defer gz.Close()
+		r = gz
+	case CompressionZstd:
+		return info, true, nil
+	}
+
+	header, e, pointerSize, err := ReadHeader(r)
+	if errors.Is(err, ErrInvalidIdentifier) || errors.Is(err, ErrShortHeader) {
+		return FileInfo{}, false, nil
+	}
+	if err != nil {
+		return info, false, err
+	}
+	info.HeaderRead = true
+	info.Header, info.Endianness, info.PointerSize = header, e, pointerSize
+	return info, true, nil
+}
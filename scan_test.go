@@ -0,0 +1,84 @@
+//go:build go1.16
+// +build go1.16
+
+package blend
+
+import (
+	"bytes"
+	"compress/gzip"
+	"errors"
+	"os"
+	"testing"
+	"testing/fstest"
+)
+
+func TestScanDir(t *testing.T) {
+	infos, err := ScanDir(os.DirFS("examples"), ".")
+	if err != nil {
+		t.Fatalf("Expected nil error, got: %v", err)
+	}
+	if len(infos) != 1 {
+		t.Fatalf("expected 1 file, got: %v", infos)
+	}
+	info := infos[0]
+	if major, minor, ok := info.Version(); info.Path != "cubus-animated.blend" || !ok || major != 2 || minor != 80 {
+		t.Errorf("expected cubus-animated.blend of version 2.80, got %s of version %d.%d", info.Path, major, minor)
+	}
+	if info.Endianness != Little || info.PointerSize != 64 || info.Compression != "" {
+		t.Errorf("expected uncompressed little endian file with 64 bit pointers, got: %+v", info)
+	}
+}
+
+func TestScanDir_mixed(t *testing.T) {
+	var gz bytes.Buffer
+	w := gzip.NewWriter(&gz)
+	w.Write(header('_', 'V', "279"))
+	w.Close()
+	fsys := fstest.MapFS{
+		"a.blend":            {Data: header('-', 'v', "280")},
+		"notes.txt":          {Data: header('-', 'v', "280")},
+		"fake.blend":         {Data: []byte("not a blend file")},
+		"nested/b.blend":     {Data: gz.Bytes()},
+		"nested/c.blend":     {Data: []byte{0x28, 0xb5, 0x2f, 0xfd, 0, 0, 0, 0, 0, 0, 0, 0}},
+		"nested/d.blend1":    {Data: header('-', 'v', "280")},
+		"nested/short.blend": {Data: []byte("BLEND")},
+		"z/corrupt.blend":    {Data: append(gz.Bytes()[:10:10], 0xff, 0xff, 0xff, 0xff, 0xff, 0xff)},
+		"z/endianness.blend": {Data: header('-', 'x', "280")},
+	}
+
+	infos, err := ScanDir(fsys, ".")
+	if err != nil {
+		t.Fatalf("Expected nil error, got: %v", err)
+	}
+	expected := []struct {
+		path        string
+		compression string
+		version     string
+	}{
+		{"a.blend", "", "280"},
+		{"nested/b.blend", CompressionGzip, "279"},
+		{"nested/c.blend", CompressionZstd, "\x00\x00\x00"},
+		{"z/endianness.blend", "", "\x00\x00\x00"},
+	}
+	if len(infos) != len(expected) {
+		t.Fatalf("expected %d files, got: %+v", len(expected), infos)
+	}
+	for i, e := range expected {
+		info := infos[i]
+		if info.Path != e.path || info.Compression != e.compression || string(info.Header.Version[:]) != e.version {
+			t.Errorf("expected %s compressed %q of version %q, got: %+v", e.path, e.compression, e.version, info)
+		}
+	}
+	if infos[1].Endianness != Big || infos[1].PointerSize != 32 {
+		t.Errorf("expected big endian file with 32 bit pointers, got: %+v", infos[1])
+	}
+
+	// the header of zstd compressed files is unknown
+	if _, _, ok := infos[2].Version(); ok || infos[2].HeaderRead || infos[2].Err != nil {
+		t.Errorf("expected zstd compressed file of unknown version, got: %+v", infos[2])
+	}
+	// files failing to read do not end the scan, while corrupt gzip streams are no blend files
+	if info := infos[3]; !errors.Is(info.Err, ErrInvalidEndianness) || info.HeaderRead {
+		t.Errorf("expected ErrInvalidEndianness for %s, got: %+v", info.Path, info)
+	}
+}
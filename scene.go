@@ -0,0 +1,302 @@
+package blend
+
+import (
+	"fmt"
+	"math"
+)
+
+// RenderSettings holds the output settings of a scene's render data.
+type RenderSettings struct {
+	// Horizontal resolution in pixels before scaling by ResolutionPercentage
+	ResolutionX int
+	// Vertical resolution in pixels before scaling by ResolutionPercentage
+	ResolutionY int
+	// Percentage the resolution is scaled by when rendering
+	ResolutionPercentage int
+	// Frames per second before dividing by FPSBase
+	FPS int
+	// Divisor of FPS, the frame rate being FPS / FPSBase, e.g. 1.001 for 29.97 fps
+	FPSBase float32
+	// Path rendered animations are written to, which may be relative to the file, e.g. "//render/"
+	OutputPath string
+}
+
+// RenderSettings decodes the render settings of the scene located at sceneAddr.
+func (f *File) RenderSettings(sceneAddr uint64) (*RenderSettings, error) {
+	sc, err := f.structAt(sceneAddr, "Scene")
+	if err != nil {
+		return nil, err
+	}
+	r, err := sc.sub("r")
+	if err != nil {
+		return nil, err
+	}
+	settings := &RenderSettings{}
+	for _, v := range []struct {
+		field string
+		dst   *int
+	}{
+		{"xsch", &settings.ResolutionX},
+		{"ysch", &settings.ResolutionY},
+		{"size", &settings.ResolutionPercentage},
+		{"frs_sec", &settings.FPS},
+	} {
+		n, err := r.int(v.field)
+		if err != nil {
+			return nil, err
+		}
+		*v.dst = int(n)
+	}
+	base, err := r.float("frs_sec_base")
+	if err != nil {
+		return nil, err
+	}
+	settings.FPSBase = float32(base)
+	if settings.OutputPath, err = r.string("pic"); err != nil {
+		return nil, err
+	}
+	return settings, nil
+}
+
+// FrameRange returns the first and last frame of the animation of the scene located at sceneAddr,
+// along with the frame which was current when the file was saved.
+func (f *File) FrameRange(sceneAddr uint64) (start, end, current int, err error) {
+	sc, err := f.structAt(sceneAddr, "Scene")
+	if err != nil {
+		return 0, 0, 0, err
+	}
+	var frames [3]int
+	for i, field := range []string{"r.sfra", "r.efra", "r.cfra"} {
+		n, err := sc.int(field)
+		if err != nil {
+			return 0, 0, 0, err
+		}
+		frames[i] = int(n)
+	}
+	return frames[0], frames[1], frames[2], nil
+}
+
+// ActiveObject returns the name, without its ID code, and the address of the active object
+// of the view layer which was active when the file was saved.
+// Files saved before Blender 2.80 store the active object in the current scene instead.
+// ErrNoActiveObject is returned if no object is active.
+func (f *File) ActiveObject() (name string, addr uint64, err error) {
+	glob, err := f.global()
+	if err != nil {
+		return "", 0, err
+	}
+	var basact uint64
+	if glob.hasField("cur_view_layer") {
+		viewLayer, err := glob.pointer("cur_view_layer")
+		if err != nil {
+			return "", 0, err
+		}
+		if viewLayer == 0 {
+			return "", 0, ErrNoActiveObject
+		}
+		layer, err := f.structAt(viewLayer, "ViewLayer")
+		if err != nil {
+			return "", 0, err
+		}
+		if basact, err = layer.pointer("basact"); err != nil {
+			return "", 0, err
+		}
+	} else {
+		scene, err := glob.pointer("curscene")
+		if err != nil {
+			return "", 0, err
+		}
+		if scene == 0 {
+			return "", 0, ErrNoActiveObject
+		}
+		sc, err := f.structAt(scene, "Scene")
+		if err != nil {
+			return "", 0, err
+		}
+		if basact, err = sc.pointer("basact"); err != nil {
+			return "", 0, err
+		}
+	}
+	if basact == 0 {
+		return "", 0, ErrNoActiveObject
+	}
+
+	base, err := f.structAt(basact, "Base")
+	if err != nil {
+		return "", 0, err
+	}
+	addr, err = base.pointer("object")
+	if err != nil {
+		return "", 0, err
+	}
+	if addr == 0 {
+		return "", 0, ErrNoActiveObject
+	}
+	ob, err := f.structAt(addr, "Object")
+	if err != nil {
+		return "", 0, err
+	}
+	name, err = ob.idName()
+	if err != nil {
+		return "", 0, err
+	}
+	return name, addr, nil
+}
+
+// ActiveCamera returns the name, without its ID code, and the address of the camera object
+// the scene located at sceneAddr renders from. ErrNoCamera is returned if the scene has no camera set.
+func (f *File) ActiveCamera(sceneAddr uint64) (name string, addr uint64, err error) {
+	sc, err := f.structAt(sceneAddr, "Scene")
+	if err != nil {
+		return "", 0, err
+	}
+	addr, err = sc.pointer("camera")
+	if err != nil {
+		return "", 0, err
+	}
+	if addr == 0 {
+		return "", 0, ErrNoCamera
+	}
+	ob, err := f.structAt(addr, "Object")
+	if err != nil {
+		return "", 0, err
+	}
+	name, err = ob.idName()
+	if err != nil {
+		return "", 0, err
+	}
+	return name, addr, nil
+}
+
+// SceneObject summarizes an object of a scene.
+type SceneObject struct {
+	// Name of the object without its ID code
+	Name string
+	// Address of the object
+	Address uint64
+	// Type of the object as named by ObjectTypeName, e.g. MESH
+	Type string
+	// World matrix of the object, indexed by column and row like Blender's obmat
+	Matrix [4][4]float32
+	// Address of the data linked to the object, e.g. its Mesh, or 0 for empties
+	Data uint64
+}
+
+// SceneObjects returns the objects of the scene located at sceneAddr in the order of its object bases.
+// Objects are collected from the bases of all view layers, each object being returned once.
+// Files saved before Blender 2.80 store the bases in the scene itself.
+func (f *File) SceneObjects(sceneAddr uint64) ([]SceneObject, error) {
+	sc, err := f.structAt(sceneAddr, "Scene")
+	if err != nil {
+		return nil, err
+	}
+	var bases []uint64
+	if sc.hasField("view_layers") {
+		first, err := sc.pointer("view_layers.first")
+		if err != nil {
+			return nil, err
+		}
+		err = f.walkList(first, func(layer *instance) error {
+			first, err := layer.pointer("object_bases.first")
+			if err != nil {
+				return err
+			}
+			bases = append(bases, first)
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	} else {
+		first, err := sc.pointer("base.first")
+		if err != nil {
+			return nil, err
+		}
+		bases = append(bases, first)
+	}
+
+	objects := []SceneObject{}
+	seen := make(map[uint64]bool)
+	for _, first := range bases {
+		err := f.walkList(first, func(base *instance) error {
+			addr, err := base.pointer("object")
+			if err != nil {
+				return err
+			}
+			if addr == 0 || seen[addr] {
+				return nil
+			}
+			seen[addr] = true
+			ob, err := f.sceneObject(addr)
+			if err != nil {
+				return err
+			}
+			objects = append(objects, *ob)
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	return objects, nil
+}
+
+// sceneObject decodes the summary of the object located at addr.
+func (f *File) sceneObject(addr uint64) (*SceneObject, error) {
+	ob, err := f.structAt(addr, "Object")
+	if err != nil {
+		return nil, err
+	}
+	name, err := ob.idName()
+	if err != nil {
+		return nil, err
+	}
+	t, err := ob.int("type")
+	if err != nil {
+		return nil, err
+	}
+	data, err := ob.pointer("data")
+	if err != nil {
+		return nil, err
+	}
+	_, mat, err := ob.field("obmat")
+	if err != nil {
+		return nil, err
+	}
+	if len(mat) != 64 {
+		return nil, fmt.Errorf("blend: field 'obmat' of %s is not a 4x4 matrix", ob.typeName())
+	}
+	so := &SceneObject{
+		Name:    name,
+		Address: addr,
+		Type:    ObjectTypeName(int(t)),
+		Data:    data,
+	}
+	for i := range so.Matrix {
+		for j := range so.Matrix[i] {
+			so.Matrix[i][j] = math.Float32frombits(f.order.Uint32(mat[16*i+4*j:]))
+		}
+	}
+	return so, nil
+}
+
+// RenderEngine returns the identifier of the render engine of the scene located at sceneAddr,
+// e.g. "CYCLES" or "BLENDER_EEVEE". Blender renders scenes without engine with its default engine,
+// which is returned in that case: "BLENDER_EEVEE" since Blender 2.80 and "BLENDER_RENDER" before.
+func (f *File) RenderEngine(sceneAddr uint64) (string, error) {
+	sc, err := f.structAt(sceneAddr, "Scene")
+	if err != nil {
+		return "", err
+	}
+	engine, err := sc.string("r.engine")
+	if err != nil {
+		return "", err
+	}
+	if engine == "" {
+		if major, minor := f.Version(); major < 2 || major == 2 && minor < 80 {
+			return "BLENDER_RENDER", nil
+		}
+		return "BLENDER_EEVEE", nil
+	}
+	return engine, nil
+}
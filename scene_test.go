@@ -0,0 +1,154 @@
+package blend
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestFile_RenderSettings(t *testing.T) {
+	f := readExampleFile(t, "cubus-animated.blend")
+
+	settings, err := f.RenderSettings(f.fileBlocks[CodeScene][0].Header.OldMemoryAddress)
+	if err != nil {
+		t.Fatalf("Expected nil error, got: %v", err)
+	}
+	expected := RenderSettings{
+		ResolutionX:          1920,
+		ResolutionY:          1080,
+		ResolutionPercentage: 100,
+		FPS:                  24,
+		FPSBase:              1,
+		OutputPath:           "/tmp/",
+	}
+	if *settings != expected {
+		t.Errorf("expected %+v, got: %+v", expected, *settings)
+	}
+
+	if _, err := f.RenderSettings(f.fileBlocks[CodeObject][0].Header.OldMemoryAddress); err == nil {
+		t.Error("expected error for an address not holding a scene")
+	}
+}
+
+func TestFile_FrameRange(t *testing.T) {
+	f := readExampleFile(t, "cubus-animated.blend")
+
+	start, end, current, err := f.FrameRange(f.fileBlocks[CodeScene][0].Header.OldMemoryAddress)
+	if err != nil {
+		t.Fatalf("Expected nil error, got: %v", err)
+	}
+	if start != 1 || end != 100 || current != 33 {
+		t.Errorf("expected frames 1 to 100 at frame 33, got %d to %d at frame %d", start, end, current)
+	}
+}
+
+func TestFile_ActiveObject(t *testing.T) {
+	fx := newFixture(t)
+	cube := fx.blockNamed(CodeObject, "OBCube")
+
+	name, addr, err := fx.f.ActiveObject()
+	if err != nil {
+		t.Fatalf("Expected nil error, got: %v", err)
+	}
+	if name != "Cube" || addr != cube.Header.OldMemoryAddress {
+		t.Errorf("expected active object 'Cube' at %#x, got '%s' at %#x", cube.Header.OldMemoryAddress, name, addr)
+	}
+}
+
+func TestFile_ActiveObjectNone(t *testing.T) {
+	fx := newFixture(t)
+	glob, err := fx.f.global()
+	if err != nil {
+		t.Fatalf("Expected nil error, got: %v", err)
+	}
+	viewLayer, err := glob.pointer("cur_view_layer")
+	if err != nil {
+		t.Fatalf("Expected nil error, got: %v", err)
+	}
+	fx.set(fx.f.addresses[viewLayer], 0, "basact", uint64(0))
+
+	if _, _, err := fx.file().ActiveObject(); !errors.Is(err, ErrNoActiveObject) {
+		t.Errorf("expected ErrNoActiveObject, got: %v", err)
+	}
+}
+
+func TestFile_ActiveCamera(t *testing.T) {
+	fx := newFixture(t)
+	camera := fx.blockNamed(CodeObject, "OBCamera")
+	scene := fx.block(CodeScene, 0)
+
+	name, addr, err := fx.f.ActiveCamera(scene.Header.OldMemoryAddress)
+	if err != nil {
+		t.Fatalf("Expected nil error, got: %v", err)
+	}
+	if name != "Camera" || addr != camera.Header.OldMemoryAddress {
+		t.Errorf("expected active camera 'Camera' at %#x, got '%s' at %#x", camera.Header.OldMemoryAddress, name, addr)
+	}
+
+	fx.set(scene, 0, "camera", uint64(0))
+	if _, _, err := fx.file().ActiveCamera(scene.Header.OldMemoryAddress); !errors.Is(err, ErrNoCamera) {
+		t.Errorf("expected ErrNoCamera, got: %v", err)
+	}
+}
+
+func TestFile_SceneObjects(t *testing.T) {
+	f := readExampleFile(t, "cubus-animated.blend")
+
+	objects, err := f.SceneObjects(f.fileBlocks[CodeScene][0].Header.OldMemoryAddress)
+	if err != nil {
+		t.Fatalf("Expected nil error, got: %v", err)
+	}
+	types := map[string]string{}
+	for _, ob := range objects {
+		types[ob.Name] = ob.Type
+	}
+	expected := map[string]string{"Cube": "MESH", "Light": "LIGHT", "Camera": "CAMERA"}
+	if len(types) != len(expected) {
+		t.Fatalf("expected objects %v, got %v", expected, types)
+	}
+	for name, typ := range expected {
+		if types[name] != typ {
+			t.Errorf("expected object '%s' of type %s, got '%s'", name, typ, types[name])
+		}
+	}
+
+	cube := objects[0]
+	if cube.Name != "Cube" {
+		t.Fatalf("expected first object 'Cube', got '%s'", cube.Name)
+	}
+	mesh := f.fileBlocks[CodeMesh][0].Header.OldMemoryAddress
+	if cube.Data != mesh {
+		t.Errorf("expected cube data at %#x, got %#x", mesh, cube.Data)
+	}
+	if cube.Matrix[2][2] != 0.75 || cube.Matrix[3] != [4]float32{0, 0, 0, 1} {
+		t.Errorf("expected rotated cube at the origin, got matrix %v", cube.Matrix)
+	}
+}
+
+func TestFile_RenderEngine(t *testing.T) {
+	fx := newFixture(t)
+	scene := fx.block(CodeScene, 0)
+
+	engine, err := fx.f.RenderEngine(scene.Header.OldMemoryAddress)
+	if err != nil {
+		t.Fatalf("Expected nil error, got: %v", err)
+	}
+	if engine != "BLENDER_EEVEE" {
+		t.Errorf("expected engine BLENDER_EEVEE, got '%s'", engine)
+	}
+
+	fx.set(scene, 0, "r.engine", "CYCLES")
+	if engine, err := fx.file().RenderEngine(scene.Header.OldMemoryAddress); err != nil || engine != "CYCLES" {
+		t.Errorf("expected engine CYCLES, got '%s' with error: %v", engine, err)
+	}
+
+	// scenes without engine use the default one of their version
+	fx.set(scene, 0, "r.engine", "")
+	f := fx.file()
+	if engine, err := f.RenderEngine(scene.Header.OldMemoryAddress); err != nil || engine != "BLENDER_EEVEE" {
+		t.Errorf("expected default engine BLENDER_EEVEE, got '%s' with error: %v", engine, err)
+	}
+	f.header.Version = [3]byte{'2', '7', '9'}
+	if engine, err := f.RenderEngine(scene.Header.OldMemoryAddress); err != nil || engine != "BLENDER_RENDER" {
+		t.Errorf("expected default engine BLENDER_RENDER before 2.80, got '%s' with error: %v", engine, err)
+	}
+}
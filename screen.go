@@ -0,0 +1,88 @@
+package blend
+
+import "fmt"
+
+// Screen represents an editor screen layout stored in a file-block with code 'SN'.
+type Screen struct {
+	// Name of the screen without its ID code
+	Name string
+	// Areas the screen is divided into
+	Areas []ScreenArea
+}
+
+// ScreenArea is a single area of a screen showing one editor.
+type ScreenArea struct {
+	// Raw value of the `spacetype` field
+	SpaceType int
+	// Editor type the area shows, e.g. VIEW_3D
+	Editor string
+}
+
+// spaceTypeNames maps the values of Blender's eSpace_Type enum to the editor type names used by the Python API.
+var spaceTypeNames = map[int]string{
+	0:  "EMPTY",
+	1:  "VIEW_3D",
+	2:  "GRAPH_EDITOR",
+	3:  "OUTLINER",
+	4:  "PROPERTIES",
+	5:  "FILE_BROWSER",
+	6:  "IMAGE_EDITOR",
+	7:  "INFO",
+	8:  "SEQUENCE_EDITOR",
+	9:  "TEXT_EDITOR",
+	12: "DOPESHEET_EDITOR",
+	13: "NLA_EDITOR",
+	14: "SCRIPT",
+	16: "NODE_EDITOR",
+	18: "CONSOLE",
+	19: "PREFERENCES",
+	20: "CLIP_EDITOR",
+	21: "TOPBAR",
+	22: "STATUSBAR",
+	23: "SPREADSHEET",
+}
+
+// SpaceTypeName returns the editor type name of a `spacetype` value, or "UNKNOWN".
+func SpaceTypeName(t int) string {
+	if name, ok := spaceTypeNames[t]; ok {
+		return name
+	}
+	return "UNKNOWN"
+}
+
+// Screens decodes all screen layouts of the file.
+func (f *File) Screens() ([]Screen, error) {
+	var screens []Screen
+	err := f.eachStruct(CodeScreen, func(sc *instance) error {
+		name, err := sc.idName()
+		if err != nil {
+			return err
+		}
+		first, err := sc.pointer("areabase.first")
+		if err != nil {
+			return err
+		}
+
+		screen := Screen{Name: name}
+		err = f.walkList(first, func(area *instance) error {
+			t, err := area.int("spacetype")
+			if err != nil {
+				return err
+			}
+			screen.Areas = append(screen.Areas, ScreenArea{
+				SpaceType: int(t),
+				Editor:    SpaceTypeName(int(t)),
+			})
+			return nil
+		})
+		if err != nil {
+			return fmt.Errorf("blend: unable to read areas of screen '%s': %w", name, err)
+		}
+		screens = append(screens, screen)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return screens, nil
+}
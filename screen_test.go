@@ -0,0 +1,42 @@
+package blend
+
+import "testing"
+
+func TestFile_Screens(t *testing.T) {
+	f := readExampleFile(t, "cubus-animated.blend")
+	screens, err := f.Screens()
+	if err != nil {
+		t.Fatalf("Expected nil error, got: %v", err)
+	}
+	if len(screens) != 15 {
+		t.Errorf("expected 15 screens, got %d", len(screens))
+	}
+
+	var layout *Screen
+	for i := range screens {
+		if screens[i].Name == "Layout" {
+			layout = &screens[i]
+		}
+	}
+	if layout == nil {
+		t.Fatalf("expected screen 'Layout', got: %v", screens)
+	}
+	expected := []string{"PROPERTIES", "OUTLINER", "DOPESHEET_EDITOR", "VIEW_3D"}
+	if len(layout.Areas) != len(expected) {
+		t.Fatalf("expected %d areas, got: %v", len(expected), layout.Areas)
+	}
+	for i, e := range expected {
+		if layout.Areas[i].Editor != e {
+			t.Errorf("expected editor %q at index %d, got: %q", e, i, layout.Areas[i].Editor)
+		}
+	}
+}
+
+func TestSpaceTypeName(t *testing.T) {
+	if n := SpaceTypeName(1); n != "VIEW_3D" {
+		t.Errorf("expected VIEW_3D, got %q", n)
+	}
+	if n := SpaceTypeName(99); n != "UNKNOWN" {
+		t.Errorf("expected UNKNOWN, got %q", n)
+	}
+}
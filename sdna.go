@@ -0,0 +1,189 @@
+package blend
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// fieldLayout describes where a single field is located within a structure.
+type fieldLayout struct {
+	name         string
+	typeIdx      int
+	pointerDepth int
+	dims         []int
+	offset       int
+	size         int
+}
+
+// Field is a structure field with its name and type resolved.
+type Field struct {
+	// Name of the field without pointer and array notation
+	Name string
+	// Name of the type of the field
+	Type string
+	// Number of pointer indirections, e.g. 2 for "**mat"
+	PointerDepth int
+	// Dimensions of the field if it is an array, e.g. [4 4] for "obmat[4][4]"
+	ArrayDims []int
+	// Offset of the field from the start of the structure in bytes
+	Offset int
+	// Size of the field in bytes
+	Size int
+}
+
+// Fields returns the resolved fields of the structure at index structIdx of Structs.
+func (s *StructureDNA) Fields(structIdx int) ([]Field, error) {
+	if structIdx < 0 || structIdx >= len(s.layouts) {
+		return nil, fmt.Errorf("blend: struct index %d out of range", structIdx)
+	}
+	layout := s.layouts[structIdx]
+	fields := make([]Field, len(layout))
+	for i, l := range layout {
+		fields[i] = Field{
+			Name:         l.name,
+			Type:         s.Types[l.typeIdx],
+			PointerDepth: l.pointerDepth,
+			ArrayDims:    l.dims,
+			Offset:       l.offset,
+			Size:         l.size,
+		}
+	}
+	return fields, nil
+}
+
+// StructByName returns the structure with the given type name, e.g. "Mesh".
+func (s *StructureDNA) StructByName(name string) (*DNAStruct, bool) {
+	idx, ok := s.structIndex(name)
+	if !ok {
+		return nil, false
+	}
+	return &s.Structs[idx], true
+}
+
+// StructIndexByName returns the index into Structs of the structure with the given type name,
+// which is what the SDNAIndex of a block holding such structures refers to.
+func (s *StructureDNA) StructIndexByName(name string) (int, bool) {
+	return s.structIndex(name)
+}
+
+// HasField reports whether the structure with the given type name has a field named fieldName,
+// which allows reading fields only present in some versions of Blender. fieldName is given without
+// pointer and array notation, e.g. "mat" for "**mat". An error is returned if there is no such structure.
+func (s *StructureDNA) HasField(structName, fieldName string) (bool, error) {
+	idx, ok := s.structIndex(structName)
+	if !ok {
+		return false, fmt.Errorf("blend: unknown struct '%s'", structName)
+	}
+	_, ok = s.field(idx, fieldName)
+	return ok, nil
+}
+
+// init builds the lookup tables and computes the field layout of every structure.
+// pointerSize is the size of a pointer in bytes.
+func (s *StructureDNA) init(pointerSize int) error {
+	s.pointerSize = pointerSize
+	s.structIdx = make(map[string]int, len(s.Structs))
+	for i, st := range s.Structs {
+		if int(st.TypeIdx) >= len(s.Types) {
+			return fmt.Errorf("blend: sdna struct %d references unknown type %d", i, st.TypeIdx)
+		}
+		s.structIdx[s.Types[st.TypeIdx]] = i
+	}
+
+	s.layouts = make([][]fieldLayout, len(s.Structs))
+	for i, st := range s.Structs {
+		layout := make([]fieldLayout, len(st.Fields))
+		offset := 0
+		for j, field := range st.Fields {
+			if int(field.TypeIdx) >= len(s.Types) || int(field.NameIdx) >= len(s.Names) {
+				return fmt.Errorf("blend: sdna struct %d field %d out of range", i, j)
+			}
+			name, depth, dims := ParseFieldName(s.Names[field.NameIdx])
+			size := int(s.Lengths[field.TypeIdx])
+			if depth > 0 {
+				size = pointerSize
+			}
+			for _, d := range dims {
+				// negative dimensions could cancel out and pass as a plausible size
+				if d <= 0 {
+					return fmt.Errorf("%w: field '%s' of struct %d has array dimension %d", ErrMalformedSDNA, s.Names[field.NameIdx], i, d)
+				}
+				size *= d
+			}
+			layout[j] = fieldLayout{
+				name:         name,
+				typeIdx:      int(field.TypeIdx),
+				pointerDepth: depth,
+				dims:         dims,
+				offset:       offset,
+				size:         size,
+			}
+			offset += size
+		}
+		s.layouts[i] = layout
+	}
+	return nil
+}
+
+// structIndex returns the index into Structs of the structure with the given type name.
+func (s *StructureDNA) structIndex(typeName string) (int, bool) {
+	idx, ok := s.structIdx[typeName]
+	return idx, ok
+}
+
+// typeName returns the type name of the structure at index idx.
+func (s *StructureDNA) typeName(idx int) string {
+	if idx < 0 || idx >= len(s.Structs) {
+		return ""
+	}
+	return s.Types[s.Structs[idx].TypeIdx]
+}
+
+// field looks up a field by name within the structure at index idx.
+func (s *StructureDNA) field(idx int, name string) (fieldLayout, bool) {
+	if idx < 0 || idx >= len(s.layouts) {
+		return fieldLayout{}, false
+	}
+	for _, l := range s.layouts[idx] {
+		if l.name == name {
+			return l, true
+		}
+	}
+	return fieldLayout{}, false
+}
+
+// ParseFieldName splits an SDNA field name like "*next", "mat[4][4]" or "(*func)()"
+// into its identifier, the number of pointer indirections and its array dimensions.
+func ParseFieldName(s string) (name string, pointerDepth int, dims []int) {
+	// function pointers are written as "(*name)()"
+	if strings.HasPrefix(s, "(") {
+		end := strings.IndexByte(s, ')')
+		if end == -1 {
+			end = len(s)
+		}
+		inner := s[1:end]
+		name = strings.TrimLeft(inner, "*")
+		return name, len(inner) - len(name), nil
+	}
+
+	name = strings.TrimLeft(s, "*")
+	pointerDepth = len(s) - len(name)
+	if i := strings.IndexByte(name, '['); i != -1 {
+		rest := name[i:]
+		name = name[:i]
+		for len(rest) > 0 && rest[0] == '[' {
+			end := strings.IndexByte(rest, ']')
+			if end == -1 {
+				break
+			}
+			d, err := strconv.Atoi(rest[1:end])
+			if err != nil {
+				break
+			}
+			dims = append(dims, d)
+			rest = rest[end+1:]
+		}
+	}
+	return name, pointerDepth, dims
+}
@@ -0,0 +1,293 @@
+package blend
+
+import (
+	"errors"
+	"io"
+	"reflect"
+	"testing"
+)
+
+func TestFile_SDNANames(t *testing.T) {
+	f := readExampleFile(t, "cubus-animated.blend")
+	sdna, err := f.readSDNA()
+	if err != nil {
+		t.Fatalf("Expected nil error, got: %v", err)
+	}
+
+	n := 0
+	err = f.SDNANames(func(idx int, name string) bool {
+		if idx != n {
+			t.Errorf("expected index %d, got %d", n, idx)
+		}
+		if name != sdna.Names[idx] {
+			t.Errorf("expected name %q at index %d, got %q", sdna.Names[idx], idx, name)
+		}
+		n++
+		return true
+	})
+	if err != nil {
+		t.Fatalf("Expected nil error, got: %v", err)
+	}
+	if n != len(sdna.Names) {
+		t.Errorf("expected %d names, got %d", len(sdna.Names), n)
+	}
+}
+
+func TestFile_SDNANamesStop(t *testing.T) {
+	f := readExampleFile(t, "cubus-animated.blend")
+
+	var names []string
+	err := f.SDNANames(func(idx int, name string) bool {
+		names = append(names, name)
+		return idx < 1
+	})
+	if err != nil {
+		t.Fatalf("Expected nil error, got: %v", err)
+	}
+	if len(names) != 2 || names[0] != "*next" || names[1] != "*prev" {
+		t.Errorf("expected names [*next *prev], got: %v", names)
+	}
+}
+
+func BenchmarkFile_readSDNA(b *testing.B) {
+	f := readExampleFile(b, "cubus-animated.blend")
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := f.readSDNA(); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkFile_SDNANames(b *testing.B) {
+	f := readExampleFile(b, "cubus-animated.blend")
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		err := f.SDNANames(func(idx int, name string) bool {
+			return true
+		})
+		if err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func TestFile_readSDNAAlignedTypeMagic(t *testing.T) {
+	f := readExampleFile(t, "cubus-animated.blend")
+	r, err := f.getFileBlockData("DNA1")
+	if err != nil {
+		t.Fatalf("Expected nil error, got: %v", err)
+	}
+	data := &alignedReader{Reader: r}
+
+	header := make([]byte, 12)
+	if _, err := io.ReadFull(data, header); err != nil {
+		t.Fatalf("Expected nil error, got: %v", err)
+	}
+	if _, err := readStrings(data, int(f.order.Uint32(header[8:]))); err != nil {
+		t.Fatalf("Expected nil error, got: %v", err)
+	}
+	if pos := data.Pos(); pos%4 == 0 {
+		t.Fatalf("expected names section of the example to end unaligned, got position %d", pos)
+	}
+	if err := data.Align(4); err != nil {
+		t.Fatalf("Expected nil error, got: %v", err)
+	}
+
+	magic, err := data.ReadMagic()
+	if err != nil {
+		t.Fatalf("Expected nil error, got: %v", err)
+	}
+	if string(magic[:]) != "TYPE" {
+		t.Errorf("expected 'TYPE' after realignment, got %q", magic)
+	}
+}
+
+func TestFile_readSDNASwappedCounts(t *testing.T) {
+	f := readExampleFile(t, "cubus-animated.blend")
+	sdna, err := f.readSDNA()
+	if err != nil {
+		t.Fatalf("Expected nil error, got: %v", err)
+	}
+	namesEnd := 12
+	for _, n := range sdna.Names {
+		namesEnd += len(n) + 1
+	}
+	typesOffset := (namesEnd+3)/4*4 + 4
+
+	testTable := map[string]int{
+		"NumNames": 8,
+		"NumTypes": typesOffset,
+	}
+	for name, offset := range testTable {
+		t.Run(name, func(t *testing.T) {
+			f := readExampleFile(t, "cubus-animated.blend")
+			data := f.fileBlocks["DNA1"][0].Data
+			// the count as it would be read if the byte order was detected wrongly
+			data[offset], data[offset+1], data[offset+2], data[offset+3] = data[offset+3], data[offset+2], data[offset+1], data[offset]
+
+			_, err := f.readSDNA()
+			if !errors.Is(err, ErrMalformedSDNA) {
+				t.Errorf("expected ErrMalformedSDNA, got: %v", err)
+			}
+		})
+	}
+}
+
+func TestFile_SDNANamesSwappedCount(t *testing.T) {
+	f := readExampleFile(t, "cubus-animated.blend")
+	data := f.fileBlocks["DNA1"][0].Data
+	data[8], data[9], data[10], data[11] = data[11], data[10], data[9], data[8]
+
+	err := f.SDNANames(func(int, string) bool { return true })
+	if !errors.Is(err, ErrMalformedSDNA) {
+		t.Errorf("expected ErrMalformedSDNA, got: %v", err)
+	}
+}
+
+func TestFile_readSDNATruncated(t *testing.T) {
+	f := readExampleFile(t, "cubus-animated.blend")
+	b := f.fileBlocks["DNA1"][0]
+	// cut off within the field list of the last struct
+	b.Data = b.Data[:len(b.Data)-2]
+
+	_, err := f.readSDNA()
+	if !errors.Is(err, ErrShortBlockData) {
+		t.Errorf("expected ErrShortBlockData, got: %v", err)
+	}
+}
+
+func TestStructureDNA_Fields(t *testing.T) {
+	f := readExampleFile(t, "cubus-animated.blend")
+	sdna, err := f.SDNA()
+	if err != nil {
+		t.Fatalf("Expected nil error, got: %v", err)
+	}
+	idx, ok := sdna.structIndex("Object")
+	if !ok {
+		t.Fatal("expected struct 'Object' to be found")
+	}
+
+	fields, err := sdna.Fields(idx)
+	if err != nil {
+		t.Fatalf("Expected nil error, got: %v", err)
+	}
+	byName := make(map[string]Field)
+	for _, field := range fields {
+		byName[field.Name] = field
+	}
+	expected := []Field{
+		{Name: "id", Type: "ID", Size: 152},
+		{Name: "adt", Type: "AnimData", PointerDepth: 1, Size: 8},
+		{Name: "mat", Type: "Material", PointerDepth: 2, Size: 8},
+		{Name: "loc", Type: "float", ArrayDims: []int{3}, Size: 12},
+		{Name: "obmat", Type: "float", ArrayDims: []int{4, 4}, Size: 64},
+	}
+	for _, e := range expected {
+		field, ok := byName[e.Name]
+		if !ok {
+			t.Errorf("expected field %q", e.Name)
+			continue
+		}
+		e.Offset = field.Offset
+		if !reflect.DeepEqual(field, e) {
+			t.Errorf("expected field %+v, got %+v", e, field)
+		}
+	}
+	if fields[0].Name != "id" || fields[0].Offset != 0 {
+		t.Errorf("expected first field 'id' at offset 0, got %+v", fields[0])
+	}
+	if fields[1].Offset != 152 {
+		t.Errorf("expected second field at offset 152, got %+v", fields[1])
+	}
+
+	if _, err := sdna.Fields(len(sdna.Structs)); err == nil {
+		t.Error("expected error for struct index out of range")
+	}
+}
+
+func TestStructureDNA_StructIndexByName(t *testing.T) {
+	f := readExampleFile(t, "cubus-animated.blend")
+	sdna, err := f.SDNA()
+	if err != nil {
+		t.Fatalf("Expected nil error, got: %v", err)
+	}
+
+	idx, ok := sdna.StructIndexByName("Object")
+	if !ok {
+		t.Fatal("expected struct Object")
+	}
+	if name := sdna.Types[sdna.Structs[idx].TypeIdx]; name != "Object" {
+		t.Errorf("expected index to resolve to Object, got %s", name)
+	}
+	for _, b := range f.fileBlocks[CodeObject] {
+		if int(b.Header.SDNAIndex) != idx {
+			t.Errorf("expected object block to reference index %d, got %d", idx, b.Header.SDNAIndex)
+		}
+	}
+	st, ok := sdna.StructByName("Object")
+	if !ok || st != &sdna.Structs[idx] {
+		t.Errorf("expected StructByName to return struct %d", idx)
+	}
+
+	if _, ok := sdna.StructIndexByName("NoSuchStruct"); ok {
+		t.Error("expected no index for unknown struct")
+	}
+	if _, ok := sdna.StructByName("NoSuchStruct"); ok {
+		t.Error("expected no struct for unknown name")
+	}
+}
+
+func TestStructureDNA_HasField(t *testing.T) {
+	f := readExampleFile(t, "cubus-animated.blend")
+	sdna, err := f.SDNA()
+	if err != nil {
+		t.Fatalf("Expected nil error, got: %v", err)
+	}
+
+	testTable := map[string]bool{
+		"totvert":   true,
+		"mvert":     true,
+		"vdata":     true,
+		"positions": false,
+		"*mvert":    false,
+	}
+	for field, expected := range testTable {
+		ok, err := sdna.HasField("Mesh", field)
+		if err != nil {
+			t.Fatalf("Expected nil error, got: %v", err)
+		}
+		if ok != expected {
+			t.Errorf("expected Mesh to have field '%s': %v, got %v", field, expected, ok)
+		}
+	}
+
+	if _, err := sdna.HasField("NoSuchStruct", "totvert"); err == nil {
+		t.Error("expected error for unknown struct")
+	}
+}
+
+func TestParseFieldName(t *testing.T) {
+	testTable := []struct {
+		field        string
+		name         string
+		pointerDepth int
+		dims         []int
+	}{
+		{"totvert", "totvert", 0, nil},
+		{"*next", "next", 1, nil},
+		{"**mat", "mat", 2, nil},
+		{"name[66]", "name", 0, []int{66}},
+		{"obmat[4][4]", "obmat", 0, []int{4, 4}},
+		{"*mtex[18]", "mtex", 1, []int{18}},
+		{"(*draw)()", "draw", 1, nil},
+	}
+	for _, tt := range testTable {
+		name, depth, dims := ParseFieldName(tt.field)
+		if name != tt.name || depth != tt.pointerDepth || !reflect.DeepEqual(dims, tt.dims) {
+			t.Errorf("expected %q to parse to (%q, %d, %v), got (%q, %d, %v)", tt.field, tt.name, tt.pointerDepth, tt.dims, name, depth, dims)
+		}
+	}
+}
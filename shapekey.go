@@ -0,0 +1,105 @@
+package blend
+
+import "fmt"
+
+// ShapeKey is a single shape of a mesh, storing a position for every vertex.
+type ShapeKey struct {
+	Name string
+	// Current influence of the key
+	Value float32
+	// Position of each vertex in this shape
+	Positions [][3]float32
+	// Offset of each vertex from its position in the key this key is relative to
+	Offsets [][3]float32
+}
+
+// ShapeKeys decodes the shape keys of the mesh located at meshAddr.
+// Meshes without shape keys return an empty slice.
+func (f *File) ShapeKeys(meshAddr uint64) ([]ShapeKey, error) {
+	me, err := f.structAt(meshAddr, "Mesh")
+	if err != nil {
+		return nil, err
+	}
+	keyAddr, err := me.pointer("key")
+	if err != nil {
+		return nil, err
+	}
+	keys := []ShapeKey{}
+	if keyAddr == 0 {
+		return keys, nil
+	}
+
+	key, err := f.structAt(keyAddr, "Key")
+	if err != nil {
+		return nil, err
+	}
+	first, err := key.pointer("block.first")
+	if err != nil {
+		return nil, err
+	}
+	var relative []int
+	err = f.walkList(first, func(kb *instance) error {
+		name, err := kb.string("name")
+		if err != nil {
+			return err
+		}
+		value, err := kb.float("curval")
+		if err != nil {
+			return err
+		}
+		rel, err := kb.int("relative")
+		if err != nil {
+			return err
+		}
+		positions, err := f.keyBlockPositions(kb)
+		if err != nil {
+			return fmt.Errorf("blend: unable to read shape key '%s': %w", name, err)
+		}
+		keys = append(keys, ShapeKey{
+			Name:      name,
+			Value:     float32(value),
+			Positions: positions,
+		})
+		relative = append(relative, int(rel))
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range keys {
+		ref := keys[i].Positions
+		if r := relative[i]; r >= 0 && r < len(keys) && len(keys[r].Positions) == len(ref) {
+			ref = keys[r].Positions
+		}
+		keys[i].Offsets = make([][3]float32, len(keys[i].Positions))
+		for v, p := range keys[i].Positions {
+			keys[i].Offsets[v] = [3]float32{p[0] - ref[v][0], p[1] - ref[v][1], p[2] - ref[v][2]}
+		}
+	}
+	return keys, nil
+}
+
+// keyBlockPositions decodes the vertex positions stored in the data of a KeyBlock.
+func (f *File) keyBlockPositions(kb *instance) ([][3]float32, error) {
+	total, err := kb.int("totelem")
+	if err != nil {
+		return nil, err
+	}
+	data, err := kb.pointer("data")
+	if err != nil {
+		return nil, err
+	}
+	if total <= 0 || data == 0 {
+		return nil, nil
+	}
+	b, err := f.blockByAddress(data)
+	if err != nil {
+		return nil, err
+	}
+	raw, err := safeSlice(b.Data, 0, int(total)*12)
+	if err != nil {
+		return nil, fmt.Errorf("blend: unable to read positions of block '%s' at %#x: %w", b.Header.Code, data, err)
+	}
+	return f.decodeVectors(raw), nil
+}
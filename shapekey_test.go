@@ -0,0 +1,75 @@
+package blend
+
+import (
+	"encoding/binary"
+	"math"
+	"testing"
+)
+
+func TestFile_ShapeKeys(t *testing.T) {
+	fx := newFixture(t)
+	me := fx.blockNamed("ME", "MECube")
+
+	basis := make([]byte, 8*12)
+	smile := make([]byte, 8*12)
+	for i := 0; i < 8*3; i++ {
+		binary.LittleEndian.PutUint32(basis[4*i:], math.Float32bits(1))
+		binary.LittleEndian.PutUint32(smile[4*i:], math.Float32bits(1))
+	}
+	binary.LittleEndian.PutUint32(smile[12*2+8:], math.Float32bits(1.5))
+	basisData := fx.addRaw("DATA", 0, 1, basis)
+	smileData := fx.addRaw("DATA", 0, 1, smile)
+
+	blocks := []*Block{fx.add("DATA", "KeyBlock", 1), fx.add("DATA", "KeyBlock", 1)}
+	fx.set(blocks[0], 0, "name", "Basis")
+	fx.set(blocks[0], 0, "totelem", 8)
+	fx.set(blocks[0], 0, "data", basisData.Header.OldMemoryAddress)
+	fx.set(blocks[0], 0, "next", blocks[1].Header.OldMemoryAddress)
+	fx.set(blocks[1], 0, "name", "Smile")
+	fx.set(blocks[1], 0, "curval", float32(0.25))
+	fx.set(blocks[1], 0, "totelem", 8)
+	fx.set(blocks[1], 0, "data", smileData.Header.OldMemoryAddress)
+
+	key := fx.add("KE", "Key", 1)
+	fx.set(key, 0, "block.first", blocks[0].Header.OldMemoryAddress)
+	fx.set(key, 0, "block.last", blocks[1].Header.OldMemoryAddress)
+	fx.set(me, 0, "key", key.Header.OldMemoryAddress)
+
+	keys, err := fx.file().ShapeKeys(me.Header.OldMemoryAddress)
+	if err != nil {
+		t.Fatalf("Expected nil error, got: %v", err)
+	}
+	if len(keys) != 2 {
+		t.Fatalf("expected 2 shape keys, got %d", len(keys))
+	}
+	if keys[0].Name != "Basis" || keys[1].Name != "Smile" {
+		t.Errorf("expected keys Basis and Smile, got %q and %q", keys[0].Name, keys[1].Name)
+	}
+	if keys[1].Value != 0.25 {
+		t.Errorf("expected value 0.25, got %v", keys[1].Value)
+	}
+	if len(keys[1].Positions) != 8 || keys[1].Positions[2] != [3]float32{1, 1, 1.5} {
+		t.Errorf("expected vertex 2 at (1, 1, 1.5), got: %v", keys[1].Positions)
+	}
+	for i, o := range keys[1].Offsets {
+		expected := [3]float32{}
+		if i == 2 {
+			expected[2] = 0.5
+		}
+		if o != expected {
+			t.Errorf("expected offset %v of vertex %d, got %v", expected, i, o)
+		}
+	}
+}
+
+func TestFile_ShapeKeysNone(t *testing.T) {
+	f := readExampleFile(t, "cubus-animated.blend")
+
+	keys, err := f.ShapeKeys(f.fileBlocks["ME"][0].Header.OldMemoryAddress)
+	if err != nil {
+		t.Fatalf("Expected nil error, got: %v", err)
+	}
+	if keys == nil || len(keys) != 0 {
+		t.Errorf("expected empty slice, got: %#v", keys)
+	}
+}
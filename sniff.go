@@ -0,0 +1,112 @@
+package blend
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"errors"
+	"io"
+)
+
+// Compression formats Blender uses to compress whole files.
+const (
+	CompressionGzip = "gzip"
+	CompressionZstd = "zstd"
+)
+
+var (
+	gzipMagic = []byte{0x1f, 0x8b}
+	zstdMagic = []byte{0x28, 0xb5, 0x2f, 0xfd}
+)
+
+// sniffLen is the number of bytes needed to identify a file, which is the length of the file header.
+const sniffLen = 12
+
+// gzipSniffLen is the number of bytes of a gzip stream inspected for a compressed file header,
+// which leaves room for the gzip header and the Huffman tables of the first deflate block.
+const gzipSniffLen = 1024
+
+// peeker is implemented by readers which can return upcoming bytes without consuming them, like *bufio.Reader.
+type peeker interface {
+	Peek(n int) ([]byte, error)
+}
+
+// Sniff reports whether r holds a blend file and how it is compressed, "" meaning uncompressed.
+// The beginning of gzip streams is decompressed to check for the file header, while zstd streams
+// are recognized by their magic number only, so for them ok means that r possibly holds a blend file.
+// The compression is also reported for compressed streams which turn out to hold no blend file.
+// If r implements Peek like *bufio.Reader, the inspected bytes are not consumed,
+// otherwise use SniffReader to read the file afterwards. Peeking at gzip streams requires
+// a buffer of at least 1024 bytes, as the default size of bufio.Reader is.
+func Sniff(r io.Reader) (ok bool, compression string, err error) {
+	_, ok, compression, err = SniffReader(r)
+	return ok, compression, err
+}
+
+// SniffReader is like Sniff, but also returns a reader which replays the inspected bytes followed by the rest of r.
+func SniffReader(r io.Reader) (replay io.Reader, ok bool, compression string, err error) {
+	var prefix []byte
+	if p, isPeeker := r.(peeker); isPeeker {
+		replay = r
+		prefix, err = p.Peek(sniffLen)
+	} else {
+		prefix = make([]byte, sniffLen)
+		var n int
+		n, err = io.ReadFull(r, prefix)
+		prefix = prefix[:n]
+		replay = io.MultiReader(bytes.NewReader(prefix), r)
+	}
+	// a stream shorter than the header is no blend file, but reading it did not fail
+	if err != nil && !errors.Is(err, io.EOF) && !errors.Is(err, io.ErrUnexpectedEOF) {
+		return replay, false, "", err
+	}
+
+	switch {
+	case bytes.HasPrefix(prefix, []byte("BLENDER")):
+		return replay, true, "", nil
+	case bytes.HasPrefix(prefix, gzipMagic):
+		replay, prefix, err = sniffMore(r, replay, prefix, gzipSniffLen)
+		if err != nil {
+			return replay, false, CompressionGzip, err
+		}
+		return replay, gzipHoldsBlend(prefix), CompressionGzip, nil
+	case bytes.HasPrefix(prefix, zstdMagic):
+		return replay, true, CompressionZstd, nil
+	}
+	return replay, false, "", nil
+}
+
+// sniffMore extends the prefix of r already inspected to n bytes, returning the reader replaying it.
+// Streams ending earlier leave the prefix shorter.
+func sniffMore(r, replay io.Reader, prefix []byte, n int) (io.Reader, []byte, error) {
+	var err error
+	if p, isPeeker := r.(peeker); isPeeker {
+		prefix, err = p.Peek(n)
+		if errors.Is(err, bufio.ErrBufferFull) {
+			err = nil
+		}
+	} else {
+		more := make([]byte, n-len(prefix))
+		var read int
+		read, err = io.ReadFull(r, more)
+		prefix = append(prefix, more[:read]...)
+		replay = io.MultiReader(bytes.NewReader(prefix), r)
+	}
+	if err != nil && !errors.Is(err, io.EOF) && !errors.Is(err, io.ErrUnexpectedEOF) {
+		return replay, prefix, err
+	}
+	return replay, prefix, nil
+}
+
+// gzipHoldsBlend reports whether the beginning of a gzip stream decompresses to the identifier of a blend file.
+func gzipHoldsBlend(prefix []byte) bool {
+	zr, err := gzip.NewReader(bytes.NewReader(prefix))
+	if err != nil {
+		return false
+	}
+	identifier := make([]byte, 7)
+	if _, err := io.ReadFull(zr, identifier); err != nil {
+		return false
+	}
+	return string(identifier) == "BLENDER"
+}
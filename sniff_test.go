@@ -0,0 +1,100 @@
+package blend
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"io"
+	"io/ioutil"
+	"testing"
+)
+
+func TestSniff(t *testing.T) {
+	compressed := bytes.NewBuffer(nil)
+	w := gzip.NewWriter(compressed)
+	w.Write(header('-', 'v', "280"))
+	w.Close()
+	archive := bytes.NewBuffer(nil)
+	w = gzip.NewWriter(archive)
+	w.Write(bytes.Repeat([]byte("not a blend file, e.g. a tar archive "), 100))
+	w.Close()
+
+	testTable := []struct {
+		name        string
+		data        []byte
+		ok          bool
+		compression string
+	}{
+		{name: "blend", data: header('-', 'v', "280"), ok: true},
+		{name: "gzip", data: compressed.Bytes(), ok: true, compression: CompressionGzip},
+		{name: "gzip other", data: archive.Bytes(), compression: CompressionGzip},
+		{name: "gzip corrupt", data: []byte{0x1f, 0x8b, 0xff, 0xff}, compression: CompressionGzip},
+		{name: "zstd", data: []byte{0x28, 0xb5, 0x2f, 0xfd, 0x00}, ok: true, compression: CompressionZstd},
+		{name: "other", data: []byte("PK\x03\x04 not a blend file")},
+		{name: "short", data: []byte("BLEN")},
+		{name: "empty"},
+	}
+	for _, tt := range testTable {
+		t.Run(tt.name, func(t *testing.T) {
+			for _, r := range []io.Reader{bytes.NewReader(tt.data), bufio.NewReader(bytes.NewReader(tt.data))} {
+				ok, compression, err := Sniff(r)
+				if err != nil {
+					t.Fatalf("Expected nil error, got: %v", err)
+				}
+				if ok != tt.ok || compression != tt.compression {
+					t.Errorf("expected (%v, %q), got (%v, %q)", tt.ok, tt.compression, ok, compression)
+				}
+			}
+		})
+	}
+}
+
+func TestSniffReader_replay(t *testing.T) {
+	name := "cubus-animated.blend"
+	r, err := readExample(name)
+	if err != nil {
+		t.Fatalf("Unable to read example file '%s': %s", name, err)
+	}
+	defer r.Close()
+
+	replay, ok, _, err := SniffReader(r)
+	if err != nil || !ok {
+		t.Fatalf("expected blend file, got %v, %v", ok, err)
+	}
+	f, err := NewFile(replay)
+	if err != nil {
+		t.Fatalf("Expected nil error, got: %v", err)
+	}
+	if err := f.Validate(); err != nil {
+		t.Errorf("Expected nil error, got: %v", err)
+	}
+}
+
+func TestSniff_peek(t *testing.T) {
+	br := bufio.NewReader(bytes.NewReader(header('-', 'v', "280")))
+	if ok, _, err := Sniff(br); err != nil || !ok {
+		t.Fatalf("expected blend file, got %v, %v", ok, err)
+	}
+	if _, err := NewFile(br); err != nil {
+		t.Errorf("Expected nil error reading header after sniffing, got: %v", err)
+	}
+}
+
+func TestSniffReader_replayGzip(t *testing.T) {
+	compressed := bytes.NewBuffer(nil)
+	w := gzip.NewWriter(compressed)
+	w.Write(header('-', 'v', "280"))
+	w.Close()
+
+	replay, ok, compression, err := SniffReader(bytes.NewReader(compressed.Bytes()))
+	if err != nil || !ok || compression != CompressionGzip {
+		t.Fatalf("expected gzip compressed blend file, got %v, %q, %v", ok, compression, err)
+	}
+	data, err := ioutil.ReadAll(replay)
+	if err != nil {
+		t.Fatalf("Expected nil error, got: %v", err)
+	}
+	if !bytes.Equal(data, compressed.Bytes()) {
+		t.Error("expected replay to return the whole compressed stream")
+	}
+}
@@ -0,0 +1,76 @@
+package blend
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Text is a text datablock, e.g. a Python script embedded into the file.
+type Text struct {
+	// Name of the datablock without its ID code
+	Name string
+	// Contents of the text with its lines joined by newlines
+	Body string
+}
+
+// Texts decodes all text datablocks of the file.
+// Files without texts return an empty slice.
+func (f *File) Texts() ([]Text, error) {
+	texts := []Text{}
+	err := f.eachStruct(CodeText, func(txt *instance) error {
+		name, err := txt.idName()
+		if err != nil {
+			return err
+		}
+		first, err := txt.pointer("lines.first")
+		if err != nil {
+			return err
+		}
+
+		var lines []string
+		err = f.walkList(first, func(tl *instance) error {
+			line, err := f.textLine(tl)
+			if err != nil {
+				return err
+			}
+			lines = append(lines, line)
+			return nil
+		})
+		if err != nil {
+			return fmt.Errorf("blend: unable to read lines of text '%s': %w", name, err)
+		}
+		texts = append(texts, Text{
+			Name: name,
+			Body: strings.Join(lines, "\n"),
+		})
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return texts, nil
+}
+
+// textLine reads the contents of a TextLine, which are stored in a raw data block.
+func (f *File) textLine(tl *instance) (string, error) {
+	addr, err := tl.pointer("line")
+	if err != nil {
+		return "", err
+	}
+	n, err := tl.int("len")
+	if err != nil {
+		return "", err
+	}
+	if addr == 0 || n == 0 {
+		return "", nil
+	}
+	b, err := f.blockByAddress(addr)
+	if err != nil {
+		return "", err
+	}
+	line, err := safeSlice(b.Data, 0, int(n))
+	if err != nil {
+		return "", fmt.Errorf("blend: unable to read line of block '%s' at %#x: %w", b.Header.Code, addr, err)
+	}
+	return string(line), nil
+}
@@ -0,0 +1,49 @@
+package blend
+
+import "testing"
+
+func TestFile_Texts(t *testing.T) {
+	fx := newFixture(t)
+	txt := fx.add(CodeText, "Text", 1)
+	fx.set(txt, 0, "id.name", "TXscript.py")
+
+	var prev *Block
+	for _, line := range []string{"import bpy", "", "print(bpy.data.objects)"} {
+		tl := fx.add("DATA", "TextLine", 1)
+		if line != "" {
+			data := fx.addRaw("DATA", 0, 1, append([]byte(line), 0))
+			fx.set(tl, 0, "line", data.Header.OldMemoryAddress)
+			fx.set(tl, 0, "len", len(line))
+		}
+		if prev == nil {
+			fx.set(txt, 0, "lines.first", tl.Header.OldMemoryAddress)
+		} else {
+			fx.set(prev, 0, "next", tl.Header.OldMemoryAddress)
+		}
+		prev = tl
+	}
+
+	texts, err := fx.file().Texts()
+	if err != nil {
+		t.Fatalf("Expected nil error, got: %v", err)
+	}
+	expected := Text{
+		Name: "script.py",
+		Body: "import bpy\n\nprint(bpy.data.objects)",
+	}
+	if len(texts) != 1 || texts[0] != expected {
+		t.Errorf("expected %+v, got: %+v", expected, texts)
+	}
+}
+
+func TestFile_TextsNone(t *testing.T) {
+	f := readExampleFile(t, "cubus-animated.blend")
+
+	texts, err := f.Texts()
+	if err != nil {
+		t.Fatalf("Expected nil error, got: %v", err)
+	}
+	if texts == nil || len(texts) != 0 {
+		t.Errorf("expected empty slice, got: %v", texts)
+	}
+}
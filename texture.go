@@ -0,0 +1,66 @@
+package blend
+
+// TextureRef is an image referenced by an image texture node of a material.
+type TextureRef struct {
+	// Unique name of the image texture node within its tree
+	Node string
+	// Name of the image datablock without its ID code
+	Image string
+	// Path of the image file, relative to the blend file if it starts with "//"
+	Filepath string
+}
+
+// MaterialTextures returns the images referenced by the image texture nodes of the material located at materialAddr,
+// including those within node groups, in the order the nodes are stored. Nodes without image are skipped,
+// and materials without node tree or image texture nodes return an empty slice.
+func (f *File) MaterialTextures(materialAddr uint64) ([]TextureRef, error) {
+	ma, err := f.structAt(materialAddr, "Material")
+	if err != nil {
+		return nil, err
+	}
+	addr, err := ma.pointer("nodetree")
+	if err != nil {
+		return nil, err
+	}
+	if addr == 0 {
+		return []TextureRef{}, nil
+	}
+	tree, err := f.NodeTree(addr)
+	if err != nil {
+		return nil, err
+	}
+	textures := []TextureRef{}
+	if err := f.nodeTextures(tree, &textures); err != nil {
+		return nil, err
+	}
+	return textures, nil
+}
+
+// nodeTextures appends the images of the image texture nodes of tree and its node groups to textures.
+func (f *File) nodeTextures(tree *NodeTree, textures *[]TextureRef) error {
+	for _, node := range tree.Nodes {
+		if node.Group != nil {
+			if err := f.nodeTextures(node.Group, textures); err != nil {
+				return err
+			}
+			continue
+		}
+		if node.Type != "ShaderNodeTexImage" || node.ID == 0 {
+			continue
+		}
+		im, err := f.structAt(node.ID, "Image")
+		if err != nil {
+			return err
+		}
+		image, err := im.idName()
+		if err != nil {
+			return err
+		}
+		path, err := imageFilepath(im)
+		if err != nil {
+			return err
+		}
+		*textures = append(*textures, TextureRef{Node: node.Name, Image: image, Filepath: path})
+	}
+	return nil
+}
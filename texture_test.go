@@ -0,0 +1,47 @@
+package blend
+
+import (
+	"reflect"
+	"testing"
+)
+
+// addTextureNode adds an image texture node using the image at image to the tree.
+func addTextureNode(fx *fixture, tree *Block, name string, image uint64) {
+	node := fx.add("DATA", "bNode", 1)
+	fx.set(node, 0, "idname", "ShaderNodeTexImage")
+	fx.set(node, 0, "name", name)
+	fx.set(node, 0, "id", image)
+	fx.set(tree, 0, "nodes.first", node.Header.OldMemoryAddress)
+	fx.set(tree, 0, "nodes.last", node.Header.OldMemoryAddress)
+}
+
+func TestFile_MaterialTextures(t *testing.T) {
+	fx := newFixture(t)
+	ma := fx.blockNamed(CodeMaterial, "MAMaterial")
+
+	textures, err := fx.f.MaterialTextures(ma.Header.OldMemoryAddress)
+	if err != nil {
+		t.Fatalf("Expected nil error, got: %v", err)
+	}
+	if textures == nil || len(textures) != 0 {
+		t.Errorf("expected empty slice, got: %#v", textures)
+	}
+
+	im := fx.block(CodeImage, 0)
+	fx.set(im, 0, "id.name", "IMWood")
+	fx.set(im, 0, "name", "//textures/wood.png")
+	group := fx.add(CodeNodeTree, "bNodeTree", 1)
+	addTextureNode(fx, group, "Image Texture", im.Header.OldMemoryAddress)
+	tree := fx.add("DATA", "bNodeTree", 1)
+	addGroupNode(fx, tree, group.Header.OldMemoryAddress)
+	fx.set(ma, 0, "nodetree", tree.Header.OldMemoryAddress)
+
+	textures, err = fx.file().MaterialTextures(ma.Header.OldMemoryAddress)
+	if err != nil {
+		t.Fatalf("Expected nil error, got: %v", err)
+	}
+	expected := []TextureRef{{Node: "Image Texture", Image: "Wood", Filepath: "//textures/wood.png"}}
+	if !reflect.DeepEqual(textures, expected) {
+		t.Errorf("expected %+v, got: %+v", expected, textures)
+	}
+}
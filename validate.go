@@ -0,0 +1,95 @@
+package blend
+
+import "fmt"
+
+// Validate runs all structural checks on the file and returns a MultiError of everything found wrong.
+// It reads the file blocks and the SDNA if that did not happen yet.
+func (f *File) Validate() error {
+	var errs MultiError
+
+	h := f.header
+	if string(h.Identifier[:]) != "BLENDER" {
+		errs = append(errs, ErrInvalidIdentifier)
+	}
+	if h.PointerSize != '-' && h.PointerSize != '_' {
+		errs = append(errs, fmt.Errorf("%w: %q", ErrInvalidPointerSize, h.PointerSize))
+	}
+	if h.Endianness != 'v' && h.Endianness != 'V' {
+		errs = append(errs, fmt.Errorf("%w: %q", ErrInvalidEndianness, h.Endianness))
+	}
+
+	if err := f.loadBlocks(); err != nil {
+		return append(errs, err)
+	}
+	if _, ok := f.fileBlocks[CodeEnd]; !ok {
+		errs = append(errs, ErrMissingEndBlock)
+	}
+	if f.expectedSize > 0 {
+		declared, err := f.DeclaredSize()
+		if err != nil {
+			return append(errs, err)
+		}
+		if size := declared + int64(len(f.trailing)); size != f.expectedSize {
+			errs = append(errs, fmt.Errorf("%w: expected %d bytes, blocks declare %d", ErrSizeMismatch, f.expectedSize, size))
+		}
+	}
+
+	sdna, err := f.SDNA()
+	if err != nil {
+		return append(errs, err)
+	}
+	errs = append(errs, sdna.validate()...)
+
+	for _, b := range f.blocks {
+		idx := int(b.Header.SDNAIndex)
+		if idx >= len(sdna.Structs) {
+			errs = append(errs, fmt.Errorf("%w: block '%s' at %#x references %d", ErrSDNAIndexOutOfRange, b.Header.Code, b.Header.OldMemoryAddress, idx))
+			continue
+		}
+		// blocks referencing the first structure hold raw data
+		if idx == 0 {
+			continue
+		}
+		size := uint32(sdna.Lengths[sdna.Structs[idx].TypeIdx])
+		if b.Header.Size != size*b.Header.Count {
+			errs = append(errs, fmt.Errorf("%w: block '%s' at %#x has %d bytes for %d %s of %d bytes",
+				ErrStructSizeMismatch, b.Header.Code, b.Header.OldMemoryAddress, b.Header.Size, b.Header.Count, sdna.typeName(idx), size))
+		}
+	}
+
+	if len(errs) == 0 {
+		return nil
+	}
+	return errs
+}
+
+// validate checks the section identifiers and that every structure's size matches the sum of its fields.
+func (s *StructureDNA) validate() []error {
+	var errs []error
+	sections := []struct {
+		id       [4]byte
+		expected string
+	}{
+		{s.Identifier, "SDNA"},
+		{s.NameID, "NAME"},
+		{s.TypeID, "TYPE"},
+		{s.LenID, "TLEN"},
+		{s.StructID, "STRC"},
+	}
+	for _, sec := range sections {
+		if string(sec.id[:]) != sec.expected {
+			errs = append(errs, fmt.Errorf("%w: expected identifier '%s', got %q", ErrInvalidSDNA, sec.expected, sec.id[:]))
+		}
+	}
+
+	for i, st := range s.Structs {
+		size := 0
+		for _, l := range s.layouts[i] {
+			size += l.size
+		}
+		if expected := int(s.Lengths[st.TypeIdx]); size != expected {
+			errs = append(errs, fmt.Errorf("%w: %s has %d bytes of fields, but a length of %d", ErrStructSizeMismatch, s.typeName(i), size, expected))
+		}
+	}
+	return errs
+}
@@ -0,0 +1,44 @@
+package blend
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestFile_Validate(t *testing.T) {
+	f := readExampleFile(t, "cubus-animated.blend")
+
+	if err := f.Validate(); err != nil {
+		t.Errorf("Expected nil error, got: %v", err)
+	}
+}
+
+func TestFile_ValidateCorrupted(t *testing.T) {
+	fx := newFixture(t)
+	fx.f.header.Endianness = 'x'
+	fx.remove("ENDB")
+	fx.blockNamed("OB", "OBCube").Header.SDNAIndex = 9999
+	me := fx.blockNamed("ME", "MECube")
+	me.Data = append(me.Data, 0)
+	me.Header.Size++
+
+	err := fx.file(WithLenientEndianness(nil)).Validate()
+	if err == nil {
+		t.Fatal("expected error validating corrupted file")
+	}
+	var multi MultiError
+	if !errors.As(err, &multi) {
+		t.Fatalf("expected MultiError, got: %T", err)
+	}
+	if len(multi) != 4 {
+		t.Errorf("expected 4 errors, got: %v", multi)
+	}
+	for _, expected := range []error{ErrInvalidEndianness, ErrMissingEndBlock, ErrSDNAIndexOutOfRange, ErrStructSizeMismatch} {
+		if !errors.Is(err, expected) {
+			t.Errorf("expected error to contain '%v', got: %v", expected, err)
+		}
+	}
+	if errors.Is(err, ErrInvalidPointerSize) {
+		t.Errorf("expected no pointer size error, got: %v", err)
+	}
+}
@@ -0,0 +1,68 @@
+package blend
+
+import "fmt"
+
+// WorkSpace represents a workspace stored in a file-block with code 'WS'.
+type WorkSpace struct {
+	// Name of the workspace without its ID code
+	Name string
+	// Layouts of the workspace, one for each window it was shown in
+	Layouts []WorkSpaceLayout
+}
+
+// WorkSpaceLayout references the screen a workspace is laid out with.
+type WorkSpaceLayout struct {
+	// Name of the layout
+	Name string
+	// Name of the screen without its ID code, see Screens
+	Screen string
+}
+
+// WorkSpaces decodes all workspaces of the file in the order of the file.
+// Files without workspaces return an empty slice.
+func (f *File) WorkSpaces() ([]WorkSpace, error) {
+	workspaces := []WorkSpace{}
+	err := f.eachStruct(CodeWorkSpace, func(ws *instance) error {
+		name, err := ws.idName()
+		if err != nil {
+			return err
+		}
+		first, err := ws.pointer("layouts.first")
+		if err != nil {
+			return err
+		}
+
+		workspace := WorkSpace{Name: name, Layouts: []WorkSpaceLayout{}}
+		err = f.walkList(first, func(l *instance) error {
+			name, err := l.string("name")
+			if err != nil {
+				return err
+			}
+			layout := WorkSpaceLayout{Name: name}
+			screen, err := l.pointer("screen")
+			if err != nil {
+				return err
+			}
+			if screen != 0 {
+				sc, err := f.structAt(screen, "bScreen")
+				if err != nil {
+					return err
+				}
+				if layout.Screen, err = sc.idName(); err != nil {
+					return err
+				}
+			}
+			workspace.Layouts = append(workspace.Layouts, layout)
+			return nil
+		})
+		if err != nil {
+			return fmt.Errorf("blend: unable to read layouts of workspace '%s': %w", name, err)
+		}
+		workspaces = append(workspaces, workspace)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return workspaces, nil
+}
@@ -0,0 +1,38 @@
+package blend
+
+import "testing"
+
+func TestFile_WorkSpaces(t *testing.T) {
+	f := readExampleFile(t, "cubus-animated.blend")
+	workspaces, err := f.WorkSpaces()
+	if err != nil {
+		t.Fatalf("Expected nil error, got: %v", err)
+	}
+	expected := []string{"Animation", "Compositing", "Layout", "Modeling", "Rendering",
+		"Scripting", "Sculpting", "Shading", "Texture Paint", "UV Editing"}
+	if len(workspaces) != len(expected) {
+		t.Fatalf("expected %d workspaces, got: %v", len(expected), workspaces)
+	}
+	for i, e := range expected {
+		ws := workspaces[i]
+		if ws.Name != e {
+			t.Errorf("expected workspace %q at index %d, got: %q", e, i, ws.Name)
+		}
+		if len(ws.Layouts) == 0 || ws.Layouts[0].Name != "Default" || ws.Layouts[0].Screen != e {
+			t.Errorf("expected layout 'Default' with screen %q for workspace %q, got: %v", e, ws.Name, ws.Layouts)
+		}
+	}
+}
+
+func TestFile_WorkSpacesNone(t *testing.T) {
+	fx := newFixture(t)
+	fx.remove(CodeWorkSpace)
+
+	workspaces, err := fx.file().WorkSpaces()
+	if err != nil {
+		t.Fatalf("Expected nil error, got: %v", err)
+	}
+	if workspaces == nil || len(workspaces) != 0 {
+		t.Errorf("expected empty slice, got: %#v", workspaces)
+	}
+}
@@ -0,0 +1,102 @@
+package blend
+
+import (
+	"bufio"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"sort"
+)
+
+// WriteTo writes the file header and all file-blocks to w, followed by any data trailing the 'ENDB' block.
+// Blocks are written in the order they were read from disk unless WithCanonicalOrder was given.
+// The size of each block header is taken from its data, so blocks whose data was replaced are written as is.
+// Writing fails if the data of a block was skipped, see WithOnlyCodes.
+func (f *File) WriteTo(w io.Writer) (int64, error) {
+	blocks, err := f.ReadAllBlocks()
+	if err != nil {
+		return 0, err
+	}
+	if f.canonicalOrder {
+		blocks = canonicalOrder(blocks)
+	}
+
+	bw := bufio.NewWriter(w)
+	cw := &countingWriter{w: bw}
+	h := f.header
+	cw.Write(h.Identifier[:])
+	cw.Write([]byte{h.PointerSize, h.Endianness})
+	cw.Write(h.Version[:])
+	for _, b := range blocks {
+		if b.Data == nil && b.Header.Size > 0 {
+			return cw.n, fmt.Errorf("blend: unable to write block '%s' at %#x: data was not read", b.Header.Code, b.Header.OldMemoryAddress)
+		}
+		f.writeBlockHeader(cw, b)
+		cw.Write(b.Data)
+	}
+	cw.Write(f.trailing)
+	if cw.err == nil {
+		cw.err = bw.Flush()
+	}
+	return cw.n, cw.err
+}
+
+// writeBlockHeader writes the header of b according to the pointer size and byte order of the file.
+func (f *File) writeBlockHeader(w io.Writer, b *Block) {
+	var code [4]byte
+	copy(code[:], b.Header.Code)
+	if f.pointerSize == 32 {
+		binary.Write(w, f.order, FileBlockHeader32{
+			Code:             code,
+			Size:             uint32(len(b.Data)),
+			OldMemoryAddress: uint32(b.Header.OldMemoryAddress),
+			SDNAIndex:        b.Header.SDNAIndex,
+			Count:            b.Header.Count,
+		})
+		return
+	}
+	binary.Write(w, f.order, FileBlockHeader64{
+		Code:             code,
+		Size:             uint32(len(b.Data)),
+		OldMemoryAddress: b.Header.OldMemoryAddress,
+		SDNAIndex:        b.Header.SDNAIndex,
+		Count:            b.Header.Count,
+	})
+}
+
+// countingWriter counts the bytes written and keeps the first error, after which all writes are dropped.
+type countingWriter struct {
+	w   io.Writer
+	n   int64
+	err error
+}
+
+func (cw *countingWriter) Write(p []byte) (int, error) {
+	if cw.err != nil {
+		return 0, cw.err
+	}
+	n, err := cw.w.Write(p)
+	cw.n += int64(n)
+	cw.err = err
+	return n, err
+}
+
+// canonicalRanks orders the blocks Blender writes at fixed positions, all other blocks rank in between.
+var canonicalRanks = map[Code]int{
+	CodeRender: -3,
+	CodeTest:   -2,
+	CodeGlobal: -1,
+	CodeDNA1:   1,
+	CodeEnd:    2,
+}
+
+// canonicalOrder returns the blocks in the order Blender writes them: 'REND', 'TEST' and 'GLOB' first,
+// followed by all datablocks and 'DATA' blocks, then 'DNA1' and 'ENDB' last.
+// The sort is stable, so 'DATA' blocks stay behind the datablock they belong to.
+func canonicalOrder(blocks []*Block) []*Block {
+	sorted := append([]*Block(nil), blocks...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return canonicalRanks[sorted[i].Header.Code] < canonicalRanks[sorted[j].Header.Code]
+	})
+	return sorted
+}
@@ -0,0 +1,164 @@
+package blend
+
+import (
+	"bytes"
+	"io/ioutil"
+	"reflect"
+	"testing"
+)
+
+func TestFile_WriteTo(t *testing.T) {
+	name := "cubus-animated.blend"
+	r, err := readExample(name)
+	if err != nil {
+		t.Fatalf("Unable to read example file '%s': %s", name, err)
+	}
+	defer r.Close()
+	original, err := ioutil.ReadAll(r)
+	if err != nil {
+		t.Fatalf("Expected nil error, got: %v", err)
+	}
+	f := parseFile(t, original)
+
+	buf := bytes.NewBuffer(nil)
+	n, err := f.WriteTo(buf)
+	if err != nil {
+		t.Fatalf("Expected nil error, got: %v", err)
+	}
+	if n != int64(buf.Len()) {
+		t.Errorf("expected %d bytes written, got %d", buf.Len(), n)
+	}
+	if !bytes.Equal(buf.Bytes(), original) {
+		t.Error("expected unmodified file to be written unchanged")
+	}
+	assertRoundTrip(t, f)
+}
+
+func TestFile_WriteToModified(t *testing.T) {
+	f := readExampleFile(t, "cubus-animated.blend")
+	decoded, err := f.DecodeBlock(CodeObject)
+	if err != nil {
+		t.Fatalf("Expected nil error, got: %v", err)
+	}
+	modified := map[string]interface{}{"empty_drawsize": float32(3)}
+	encoded, err := f.EncodeBlock(CodeObject, []map[string]interface{}{modified})
+	if err != nil {
+		t.Fatalf("Expected nil error, got: %v", err)
+	}
+	b := f.fileBlocks[CodeObject][0]
+	b.Data = encoded
+
+	written := assertRoundTrip(t, f)
+	decoded, err = written.DecodeBlock(CodeObject)
+	if err != nil {
+		t.Fatalf("Expected nil error, got: %v", err)
+	}
+	if size := decoded[0]["empty_drawsize"]; size != float32(3) {
+		t.Errorf("expected modified empty_drawsize 3, got: %v", size)
+	}
+}
+
+// assertRoundTrip writes original, parses the output again and checks that it holds the same blocks in the order
+// they are written and that all datablocks decode to the same values. It returns the parsed output.
+func assertRoundTrip(t *testing.T, original *File) *File {
+	t.Helper()
+	buf := bytes.NewBuffer(nil)
+	if _, err := original.WriteTo(buf); err != nil {
+		t.Fatalf("round trip: unable to write file: %v", err)
+	}
+	written, err := NewFile(bytes.NewReader(buf.Bytes()))
+	if err != nil {
+		t.Fatalf("round trip: unable to parse written file: %v", err)
+	}
+	blocks, err := written.ReadAllBlocks()
+	if err != nil {
+		t.Fatalf("round trip: unable to read written blocks: %v", err)
+	}
+
+	expected, err := original.ReadAllBlocks()
+	if err != nil {
+		t.Fatalf("round trip: unable to read original blocks: %v", err)
+	}
+	if original.canonicalOrder {
+		expected = canonicalOrder(expected)
+	}
+	if len(blocks) != len(expected) {
+		t.Fatalf("round trip: expected %d blocks, got %d", len(expected), len(blocks))
+	}
+	for i, b := range blocks {
+		e := expected[i]
+		header := e.Header
+		header.Size = uint32(len(e.Data))
+		if b.Header != header {
+			t.Fatalf("round trip: expected block %d with header %+v, got %+v", i, header, b.Header)
+		}
+		if !bytes.Equal(b.Data, e.Data) {
+			t.Fatalf("round trip: expected data of block %d '%s' to be written unchanged", i, header.Code)
+		}
+		if header.Code == CodeData || !original.isStructured(e) {
+			continue
+		}
+		want, err := original.decodeBlock(e)
+		if err != nil {
+			t.Fatalf("round trip: unable to decode original block %d '%s': %v", i, header.Code, err)
+		}
+		got, err := written.decodeBlock(b)
+		if err != nil {
+			t.Fatalf("round trip: unable to decode written block %d '%s': %v", i, header.Code, err)
+		}
+		if !reflect.DeepEqual(got, want) {
+			t.Fatalf("round trip: expected block %d '%s' to decode to %v, got %v", i, header.Code, want, got)
+		}
+	}
+	if !bytes.Equal(written.TrailingData(), original.TrailingData()) {
+		t.Errorf("round trip: expected trailing data %q, got %q", original.TrailingData(), written.TrailingData())
+	}
+	return written
+}
+
+func TestWithCanonicalOrder(t *testing.T) {
+	f := newFixture(t).file(WithCanonicalOrder())
+	// move 'DNA1' and 'ENDB' to the front, as if they had been read from there
+	var reordered, rest []*Block
+	for _, b := range f.blocks {
+		if b.Header.Code == CodeDNA1 || b.Header.Code == CodeEnd {
+			reordered = append(reordered, b)
+		} else {
+			rest = append(rest, b)
+		}
+	}
+	f.blocks = append(reordered, rest...)
+
+	buf := bytes.NewBuffer(nil)
+	if _, err := f.WriteTo(buf); err != nil {
+		t.Fatalf("Expected nil error, got: %v", err)
+	}
+	blocks, err := parseFile(t, buf.Bytes()).ReadAllBlocks()
+	if err != nil {
+		t.Fatalf("Expected nil error, got: %v", err)
+	}
+	if len(blocks) != len(f.blocks) {
+		t.Fatalf("expected %d blocks, got %d", len(f.blocks), len(blocks))
+	}
+	if code := blocks[len(blocks)-1].Header.Code; code != CodeEnd {
+		t.Errorf("expected 'ENDB' as last block, got '%s'", code)
+	}
+	if code := blocks[len(blocks)-2].Header.Code; code != CodeDNA1 {
+		t.Errorf("expected 'DNA1' before 'ENDB', got '%s'", code)
+	}
+	for i, b := range rest {
+		if blocks[i].Header != b.Header {
+			t.Fatalf("expected block %d to be '%s', got '%s'", i, b.Header.Code, blocks[i].Header.Code)
+		}
+	}
+	assertRoundTrip(t, f)
+}
+
+func TestFile_WriteToSkippedData(t *testing.T) {
+	f := readExampleFile(t, "cubus-animated.blend")
+	f.fileBlocks[CodeObject][0].Data = nil
+
+	if _, err := f.WriteTo(ioutil.Discard); err == nil {
+		t.Error("expected error writing a block whose data was not read")
+	}
+}